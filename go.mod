@@ -4,11 +4,12 @@ go 1.22.4
 
 require (
 	github.com/baldurstod/go-printful-api-model v0.1.6
-	github.com/baldurstod/go-printful-sdk v0.3.3
+	github.com/baldurstod/go-printful-sdk v0.3.4
 	github.com/baldurstod/printful-api-model v0.0.37
 	github.com/baldurstod/randstr v0.0.1
 	github.com/gin-contrib/cors v1.7.3
 	github.com/gin-gonic/gin v1.10.0
+	github.com/go-playground/validator/v10 v10.23.0
 	github.com/lib/pq v1.12.3
 	github.com/mitchellh/mapstructure v1.5.0
 	golang.org/x/image v0.24.0
@@ -23,7 +24,6 @@ require (
 	github.com/gin-contrib/sse v0.1.0 // indirect
 	github.com/go-playground/locales v0.14.1 // indirect
 	github.com/go-playground/universal-translator v0.18.1 // indirect
-	github.com/go-playground/validator/v10 v10.23.0 // indirect
 	github.com/goccy/go-json v0.10.4 // indirect
 	github.com/icza/gox v0.2.0 // indirect
 	github.com/json-iterator/go v1.1.12 // indirect
@@ -46,3 +46,6 @@ require (
 	google.golang.org/protobuf v1.36.1 // indirect
 	gopkg.in/yaml.v3 v3.0.1 // indirect
 )
+
+// HTTPError accessors, until v0.3.4 is published
+replace github.com/baldurstod/go-printful-sdk v0.3.4 => ./third_party/go-printful-sdk
@@ -2,26 +2,29 @@ package api
 
 import (
 	"encoding/base64"
+	"encoding/json"
 	"errors"
+	"go-printful-api/src/apierrors"
 	"go-printful-api/src/config"
 	"go-printful-api/src/database"
 	"go-printful-api/src/model"
-	removeme "go-printful-api/src/model/requests"
+	"go-printful-api/src/model/requests"
 	"go-printful-api/src/printful"
 	"image"
 	"image/png"
+	"io"
 	"log"
 	_ "net/http"
 	"net/url"
-	"slices"
+	"strconv"
 	"strings"
 
-	"github.com/baldurstod/go-printful-api-model/requests"
-	printfulsdk "github.com/baldurstod/go-printful-sdk"
+	apimodel "github.com/baldurstod/go-printful-api-model/requests"
+	printfulmodel "github.com/baldurstod/go-printful-sdk/model"
 
 	"github.com/baldurstod/randstr"
 	"github.com/gin-gonic/gin"
-	"github.com/mitchellh/mapstructure"
+	"github.com/gin-gonic/gin/binding"
 	"golang.org/x/image/draw"
 )
 
@@ -35,378 +38,582 @@ type ApiRequest struct {
 	Action  string                 `json:"action" binding:"required"`
 	Version int                    `json:"version" binding:"required"`
 	Params  map[string]interface{} `json:"params"`
+	// Store used by the action. The X-Store-Id header is used if not set, then the default store
+	Store string `json:"store"`
 }
 
+// Handlers of each action, by version
+var actions = map[string]versions{
+	"get-categories": {
+		1: typedAction[requests.GetCategoriesRequest]{getCategories},
+		2: typedAction[requests.GetCategoriesRequest]{getCategoriesV2},
+	},
+	"get-countries":   {1: typedAction[requests.GetCountriesRequest]{getCountries}},
+	"get-products":    {1: typedAction[requests.GetProductsRequest]{getProducts}},
+	"search-products": {1: typedAction[requests.SearchProductsRequest]{searchProducts}},
+	"get-product": {
+		1: typedAction[requests.GetProductRequest]{getProduct},
+		2: typedAction[requests.GetProductV2Request]{getProductV2},
+	},
+	"get-product-prices":       {1: typedAction[requests.GetProductPricesRequest]{getProductPrices}},
+	"get-price-history":        {1: typedAction[requests.GetPriceHistoryRequest]{getPriceHistory}},
+	"get-price-changes":        {1: typedAction[requests.GetPriceChangesRequest]{getPriceChanges}},
+	"get-catalog-changes":      {1: typedAction[requests.GetCatalogChangesRequest]{getCatalogChanges}},
+	"get-refresh-progress":     {1: typedAction[requests.EmptyRequest]{getRefreshProgress}},
+	"get-refresh-status":       {1: typedAction[requests.RefreshRunRequest]{getRefreshStatus}},
+	"retry-failed":             {1: typedAction[requests.RefreshRunRequest]{retryFailed}},
+	"cancel-refresh":           {1: typedAction[requests.EmptyRequest]{cancelRefresh}},
+	"refresh-product":          {1: typedAction[requests.RefreshProductRequest]{refreshProduct}},
+	"get-cache-stats":          {1: typedAction[requests.EmptyRequest]{getCacheStats}},
+	"get-variant":              {1: typedAction[requests.GetVariantRequest]{getVariant}},
+	"get-available-variants":   {1: typedAction[requests.GetAvailableVariantsRequest]{getAvailableVariants}},
+	"get-similar-variants":     {1: typedAction[requests.GetSimilarVariantsRequest]{getSimilarVariants}},
+	"get-mockup-templates":     {1: typedAction[requests.ProductIDRequest]{getMockupTemplates}},
+	"get-mockup-styles":        {1: typedAction[requests.ProductIDRequest]{getMockupStyles}},
+	"get-product-images":       {1: typedAction[requests.GetProductImagesRequest]{getProductImages}},
+	"create-sync-product":      {1: typedAction[model.CreateSyncProductDatas]{createSyncProduct}},
+	"get-sync-product":         {1: typedAction[requests.GetSyncProductRequest]{getSyncProduct}},
+	"calculate-shipping-rates": {1: typedAction[apimodel.CalculateShippingRates]{calculateShippingRates}},
+	"calculate-tax-rate":       {1: typedAction[apimodel.CalculateTaxRate]{calculateTaxRate}},
+	"create-order":             {1: typedAction[apimodel.CreateOrder]{createOrder}},
+	"add-images":               {1: typedAction[requests.AddImagesRequest]{addImages}},
+	"create-api-key":           {1: typedAction[requests.CreateApiKeyRequest]{createApiKey}},
+	"revoke-api-key":           {1: typedAction[requests.RevokeApiKeyRequest]{revokeApiKey}},
+	"get-api-keys":             {1: typedAction[requests.EmptyRequest]{getApiKeys}},
+}
+
+// Handle a single request or a batch of requests when the body is an array
 func ApiHandler(c *gin.Context) {
-	var request ApiRequest
-	var err error
+	body, err := io.ReadAll(c.Request.Body)
+	if err != nil {
+		jsonError(c, apierrors.Validation("bad request", err))
+		return
+	}
 
-	if err = c.ShouldBindJSON(&request); err != nil {
-		log.Println(err)
-		jsonError(c, errors.New("bad request"))
+	if isBatch(body) {
+		batchHandler(c, body)
 		return
 	}
 
-	switch request.Action {
-	case "get-categories":
-		err = getCategories(c)
-	case "get-countries":
-		err = getCountries(c)
-	case "get-products":
-		err = getProducts(c, request.Params)
-	case "get-product":
-		err = getProduct(c, request.Params)
-	case "get-product-prices":
-		err = getProductPrices(c, request.Params)
-	case "get-variant":
-		err = getVariant(c, request.Params)
-	case "get-similar-variants":
-		err = getSimilarVariants(c, request.Params)
-	case "get-mockup-templates":
-		err = getMockupTemplates(c, request.Params)
-	case "get-mockup-styles":
-		err = getMockupStyles(c, request.Params)
-	case "create-sync-product":
-		err = createSyncProduct(c, request.Params)
-	case "get-sync-product":
-		err = getSyncProduct(c, request.Params)
-	case "calculate-shipping-rates":
-		err = calculateShippingRates(c, request.Params)
-	case "calculate-tax-rate":
-		err = calculateTaxRate(c, request.Params)
-	case "create-order":
-		err = createOrder(c, request.Params)
-	case "add-images":
-		err = addImages(c, request.Params)
-	default:
-		jsonError(c, NotFoundError{})
+	var request ApiRequest
+	if err = decodeApiRequest(body, &request); err != nil {
+		log.Println(err)
+		jsonError(c, apierrors.Validation("bad request", err))
 		return
 	}
 
+	result, err := runAction(c, &request)
 	if err != nil {
 		jsonError(c, err)
+		return
 	}
+
+	jsonSuccess(c, result)
+}
+
+func decodeApiRequest(data []byte, request *ApiRequest) error {
+	if err := json.Unmarshal(data, request); err != nil {
+		return err
+	}
+	return binding.Validator.ValidateStruct(request)
 }
 
-func getCategories(c *gin.Context) error {
-	categories, err := printful.GetCategories()
+func runAction(c *gin.Context, request *ApiRequest) (any, error) {
+	versions, found := actions[request.Action]
+	if !found {
+		return nil, apierrors.NotFound("Not found", nil)
+	}
+
+	if err := authorize(c, request.Action); err != nil {
+		return nil, err
+	}
+
+	action, found := versions[request.Version]
+	if !found {
+		return nil, apierrors.Validation("unsupported version "+strconv.Itoa(request.Version)+" of action "+request.Action+", supported versions are "+versions.String(), nil)
+	}
 
+	storeID, err := selectStore(c, request.Action, request.Store)
 	if err != nil {
-		return err
+		return nil, err
 	}
 
-	jsonSuccess(c, categories)
+	// Only requests which can run are charged
+	if err := limitRate(c, request.Action); err != nil {
+		return nil, err
+	}
 
-	return nil
+	// Requests of a batch may use different stores
+	actionContext := c.Copy()
+	actionContext.Set(storeContextKey, storeID)
+
+	return action.handle(actionContext, request.Params)
 }
 
-func getCountries(c *gin.Context) error {
-	countries, err := printful.GetCountries()
+func getCategories(c *gin.Context, request *requests.GetCategoriesRequest) (any, error) {
+	categories, err := printful.GetCategories(requestStore(c), request.Language)
 
 	if err != nil {
-		return err
+		return nil, err
 	}
 
-	jsonSuccess(c, countries)
-
-	return nil
+	return listResult(categories, func(c printfulmodel.Category) int { return c.ID }, request.ListRequest)
 }
 
-func getProducts(c *gin.Context, params map[string]interface{}) error {
-	language, _ := params["language"].(string)
-	if !slices.Contains(printfulsdk.Languages, language) {
-		language = "en_US"
+// Same as v1, with the category tree
+func getCategoriesV2(c *gin.Context, request *requests.GetCategoriesRequest) (any, error) {
+	categories, err := printful.GetCategories(requestStore(c), request.Language)
+
+	if err != nil {
+		return nil, err
 	}
 
-	products, err := printful.GetProducts(language)
+	if !paginated(request.ListRequest) {
+		projected, err := project(categories, request.Fields)
+		if err != nil {
+			return nil, err
+		}
+
+		return map[string]interface{}{
+			"categories": projected,
+			"tree":       printful.BuildCategoryTree(categories),
+		}, nil
+	}
 
+	page, next, err := paginate(categories, func(c printfulmodel.Category) int { return c.ID }, request.ListRequest)
 	if err != nil {
-		return err
+		return nil, err
 	}
 
-	jsonSuccess(c, products)
+	projected, err := project(page, request.Fields)
+	if err != nil {
+		return nil, err
+	}
 
-	return nil
+	// The tree can't be split in pages, it is built from all the categories
+	return map[string]interface{}{
+		"items":       projected,
+		"next_cursor": next,
+		"tree":        printful.BuildCategoryTree(categories),
+	}, nil
 }
 
-func getProduct(c *gin.Context, params map[string]interface{}) error {
-	productID, ok := params["product_id"].(float64)
-	if !ok {
-		return errors.New("Error while decoding param product_id")
+func getCountries(c *gin.Context, request *requests.GetCountriesRequest) (any, error) {
+	countries, err := printful.GetCountries()
+
+	if err != nil {
+		return nil, err
 	}
 
-	language, _ := params["language"].(string)
-	if !slices.Contains(printfulsdk.Languages, language) {
-		language = "en_US"
+	result, err := listResult(countries, func(c printfulmodel.Country) string { return c.Code }, request.ListRequest)
+	if err != nil {
+		return nil, err
 	}
 
-	product, err := printful.GetProduct(int(productID))
+	return result, nil
+}
+
+func getProducts(c *gin.Context, request *requests.GetProductsRequest) (any, error) {
+	products, err := printful.GetProducts(requestStore(c), request.Language)
 
 	if err != nil {
-		return err
+		return nil, err
+	}
+
+	result, err := listResult(products, func(p printfulmodel.Product) int { return p.ID }, request.ListRequest)
+	if err != nil {
+		return nil, err
 	}
 
-	variants, err := printful.GetVariants(int(productID))
+	return result, nil
+}
+
+func searchProducts(c *gin.Context, request *requests.SearchProductsRequest) (any, error) {
+	result, err := printful.SearchProducts(requestStore(c), *request)
 
 	if err != nil {
-		return err
+		return nil, err
 	}
 
-	translation, err := printful.GetProductTranslation(int(productID), language)
+	return result, nil
+}
+
+func getProduct(c *gin.Context, request *requests.GetProductRequest) (any, error) {
+	product, err := printful.GetProduct(requestStore(c), request.ProductID)
+
+	if err != nil {
+		return nil, err
+	}
+
+	variants, err := printful.GetVariants(requestStore(c), request.ProductID, request.Language)
+
+	if err != nil {
+		return nil, err
+	}
 
-	jsonSuccess(c, map[string]interface{}{
+	translation, err := printful.GetProductTranslation(request.ProductID, request.Language)
+
+	// Tagged images are optional
+	images, err := printful.GetTaggedImages(request.ProductID)
+	if err != nil {
+		log.Println("error while getting tagged images:", err)
+		images = []database.TaggedImage{}
+	}
+
+	return map[string]interface{}{
 		"product":     product,
 		"variants":    variants,
 		"translation": translation,
-	})
-
-	return nil
+		"images":      images,
+	}, nil
 }
 
-func getProductPrices(c *gin.Context, params map[string]interface{}) error {
-	productID, ok := params["product_id"].(float64)
-	if !ok {
-		return errors.New("Error while decoding param product_id")
-	}
-	currency, ok := params["currency"].(string)
-	if !ok {
-		return errors.New("Error while decoding param currency")
+// Version 2 returns the product localized, and the variants available in a country when country_code is set
+func getProductV2(c *gin.Context, request *requests.GetProductV2Request) (any, error) {
+	product, err := printful.GetLocalizedProduct(requestStore(c), request.ProductID, request.Language)
+	if err != nil {
+		return nil, err
 	}
 
-	prices, err := printful.GetProductPrices(int(productID), currency)
+	variants, err := printful.GetVariants(requestStore(c), request.ProductID, request.Language)
+	if err != nil {
+		return nil, err
+	}
 
+	// Tagged images are optional
+	images, err := printful.GetTaggedImages(request.ProductID)
 	if err != nil {
-		return err
+		log.Println("error while getting tagged images:", err)
+		images = []database.TaggedImage{}
 	}
 
-	jsonSuccess(c, prices)
+	result := map[string]interface{}{
+		"product":  product,
+		"variants": variants,
+		"images":   images,
+	}
 
-	return nil
-}
+	if request.CountryCode != "" {
+		available, err := printful.GetAvailableVariants(requestStore(c), request.ProductID, request.CountryCode, request.Language)
+		if err != nil {
+			return nil, err
+		}
 
-func getVariant(c *gin.Context, params map[string]interface{}) error {
-	variantID, ok := params["variant_id"].(float64)
-	if !ok {
-		return errors.New("Error while decoding param variant_id")
+		ids := make([]int, 0, len(available))
+		for _, variant := range available {
+			ids = append(ids, variant.ID)
+		}
+		result["available_variant_ids"] = ids
 	}
 
-	variant, err := printful.GetVariant(int(variantID))
-	log.Println(params)
+	return result, nil
+}
 
+func getProductPrices(c *gin.Context, request *requests.GetProductPricesRequest) (any, error) {
+	currency, err := requestCurrency(c, request.Currency)
 	if err != nil {
-		return err
+		return nil, err
 	}
 
-	jsonSuccess(c, variant)
+	prices, err := printful.GetProductPrices(requestStore(c), request.ProductID, currency)
+
+	if err != nil {
+		return nil, err
+	}
 
-	return nil
+	return prices, nil
 }
 
-func getSimilarVariants(c *gin.Context, params map[string]interface{}) error {
-	log.Println("getSimilarVariants", params)
-	variantID, ok := params["variant_id"].(float64)
-	if !ok {
-		return errors.New("Error while decoding param variant_id")
+func getPriceHistory(c *gin.Context, request *requests.GetPriceHistoryRequest) (any, error) {
+	currency, err := requestCurrency(c, request.Currency)
+	if err != nil {
+		return nil, err
 	}
 
-	placements, ok := params["placements"]
-	if !ok {
-		return errors.New("Error while decoding param placements")
+	// Returns all variants if variant_id is not set
+	history, err := printful.GetPriceHistory(request.ProductID, request.VariantID, currency)
+
+	if err != nil {
+		return nil, err
 	}
 
-	getSimilarVariantsPlacement := make([]printful.GetSimilarVariantsPlacement, 0)
-	err := mapstructure.Decode(placements, &getSimilarVariantsPlacement)
+	return map[string]interface{}{
+		"history": history,
+	}, nil
+}
+
+func getPriceChanges(c *gin.Context, request *requests.GetPriceChangesRequest) (any, error) {
+	currency, err := requestCurrency(c, request.Currency)
 	if err != nil {
-		return err
+		return nil, err
 	}
 
-	variantIds, err := printful.GetSimilarVariants(int(variantID), getSimilarVariantsPlacement)
-	log.Println(variantIds, err)
+	changes, err := printful.GetPriceChanges(currency, request.Days)
 
-	jsonSuccess(c, variantIds)
+	if err != nil {
+		return nil, err
+	}
 
-	return nil
+	return map[string]interface{}{
+		"changes": changes,
+	}, nil
 }
 
-func getMockupTemplates(c *gin.Context, params map[string]interface{}) error {
-	productID, ok := params["product_id"].(float64)
-	if !ok {
-		return errors.New("Error while decoding param product_id")
+func getCatalogChanges(c *gin.Context, request *requests.GetCatalogChangesRequest) (any, error) {
+	changes, err := printful.GetCatalogChanges(request.Since)
+
+	if err != nil {
+		return nil, err
 	}
 
-	templates, err := printful.GetMockupTemplates(int(productID))
-	log.Println(params)
+	return map[string]interface{}{
+		"changes": changes,
+	}, nil
+}
+
+func getRefreshProgress(c *gin.Context, request *requests.EmptyRequest) (any, error) {
+	return printful.GetRefreshProgress(), nil
+}
+
+func getRefreshStatus(c *gin.Context, request *requests.RefreshRunRequest) (any, error) {
+	// Returns the latest run if run_id is not set
+	status, err := printful.GetRefreshStatus(request.RunID)
 
 	if err != nil {
-		return err
+		return nil, err
 	}
 
-	jsonSuccess(c, map[string]interface{}{
-		"templates": templates,
-	})
-
-	return nil
+	return status, nil
 }
 
-func getMockupStyles(c *gin.Context, params map[string]interface{}) error {
-	productID, ok := params["product_id"].(float64)
-	if !ok {
-		return errors.New("Error while decoding param product_id")
+func retryFailed(c *gin.Context, request *requests.RefreshRunRequest) (any, error) {
+	// Retrying may take a while, progress can be followed with get-refresh-status
+	// Retries the latest run if run_id is not set
+	if err := printful.StartRetryFailedRefresh(request.RunID); err != nil {
+		return nil, err
 	}
 
-	styles, err := printful.GetMockupStyles(int(productID))
-	log.Println(params)
+	return map[string]interface{}{
+		"started": true,
+	}, nil
+}
 
-	if err != nil {
-		return err
+func cancelRefresh(c *gin.Context, request *requests.EmptyRequest) (any, error) {
+	return map[string]interface{}{
+		"cancelled": printful.CancelRefresh(),
+	}, nil
+}
+
+func refreshProduct(c *gin.Context, request *requests.RefreshProductRequest) (any, error) {
+	if err := printful.RefreshProduct(request.ProductID); err != nil {
+		return nil, apierrors.Wrap("Error while refreshing product", err)
 	}
 
-	jsonSuccess(c, map[string]interface{}{
-		"styles": styles,
-	})
+	return map[string]interface{}{
+		"product_id": request.ProductID,
+	}, nil
+}
 
-	return nil
+func getCacheStats(c *gin.Context, request *requests.EmptyRequest) (any, error) {
+	return map[string]interface{}{
+		"caches": database.GetCacheStats(),
+	}, nil
 }
 
-func createSyncProduct(c *gin.Context, params map[string]interface{}) error {
-	createSyncProductRequest := model.CreateSyncProductDatas{}
-	err := mapstructure.Decode(params, &createSyncProductRequest)
+func getVariant(c *gin.Context, request *requests.GetVariantRequest) (any, error) {
+	variant, err := printful.GetVariant(requestStore(c), request.VariantID, request.Language)
+
 	if err != nil {
-		log.Println(err)
-		return errors.New("Error while decoding params")
+		return nil, err
 	}
 
-	syncProduct, err := printful.CreateSyncProduct(createSyncProductRequest)
-	log.Println(syncProduct, err)
+	return variant, nil
+}
 
-	jsonSuccess(c, syncProduct)
+func getAvailableVariants(c *gin.Context, request *requests.GetAvailableVariantsRequest) (any, error) {
+	variants, err := printful.GetAvailableVariants(requestStore(c), request.ProductID, request.CountryCode, request.Language)
 
-	return nil
+	if err != nil {
+		return nil, err
+	}
+
+	return map[string]interface{}{
+		"variants": variants,
+	}, nil
 }
 
-func getSyncProduct(c *gin.Context, params map[string]interface{}) error {
-	syncProductID, ok := params["sync_product_id"].(float64)
-	if !ok {
-		return errors.New("Error while decoding param sync_product_id")
+func getSimilarVariants(c *gin.Context, request *requests.GetSimilarVariantsRequest) (any, error) {
+	placements := make([]printful.GetSimilarVariantsPlacement, 0, len(request.Placements))
+	for _, placement := range request.Placements {
+		placements = append(placements, printful.GetSimilarVariantsPlacement(placement))
 	}
 
-	product, err := printful.GetSyncProduct(int64(syncProductID))
-	log.Println(product, params)
+	variantIds, err := printful.GetSimilarVariants(request.VariantID, placements)
+	if err != nil {
+		return nil, apierrors.Wrap("Error while getting similar variants", err)
+	}
+
+	return variantIds, nil
+}
+
+func getMockupTemplates(c *gin.Context, request *requests.ProductIDRequest) (any, error) {
+	templates, err := printful.GetMockupTemplates(requestStore(c), request.ProductID)
 
 	if err != nil {
-		return err
+		return nil, err
 	}
 
-	jsonSuccess(c, product)
+	return map[string]interface{}{
+		"templates": templates,
+	}, nil
+}
+
+func getMockupStyles(c *gin.Context, request *requests.ProductIDRequest) (any, error) {
+	styles, err := printful.GetMockupStyles(request.ProductID)
+
+	if err != nil {
+		return nil, err
+	}
 
-	return nil
+	return map[string]interface{}{
+		"styles": styles,
+	}, nil
 }
 
-func calculateShippingRates(c *gin.Context, params map[string]interface{}) error {
-	calculateShippingRatesRequest := requests.CalculateShippingRates{}
-	err := mapstructure.Decode(params, &calculateShippingRatesRequest)
+func getProductImages(c *gin.Context, request *requests.GetProductImagesRequest) (any, error) {
+	images, err := printful.GetProductImages(request.ProductID, request.Color, request.MockupStyleID)
 	if err != nil {
-		log.Println(err)
-		return errors.New("Error while decoding params")
+		return nil, err
 	}
 
-	shippingRates, err := printful.CalculateShippingRates(calculateShippingRatesRequest)
-	log.Println(shippingRates, err)
+	return map[string]interface{}{
+		"images": images,
+	}, nil
+}
+
+func createSyncProduct(c *gin.Context, request *model.CreateSyncProductDatas) (any, error) {
+	size := imageDataSize(request.Image)
+	refund, err := consumeQuota(c, quotaUploadBytes, size)
 	if err != nil {
-		log.Println(err)
-		return errors.New("Error while calculating shipping rates")
+		return nil, err
 	}
 
-	jsonSuccess(c, shippingRates)
+	syncProduct, err := printful.CreateSyncProduct(requestStore(c), *request)
+	if err != nil {
+		refund(size)
+		return nil, apierrors.Wrap("Error while creating sync product", err)
+	}
 
-	return nil
+	return syncProduct, nil
 }
 
-func calculateTaxRate(c *gin.Context, params map[string]interface{}) error {
-	calculateTaxRateRequest := requests.CalculateTaxRate{}
-	err := mapstructure.Decode(params, &calculateTaxRateRequest)
+func getSyncProduct(c *gin.Context, request *requests.GetSyncProductRequest) (any, error) {
+	product, err := printful.GetSyncProduct(requestStore(c), request.SyncProductID)
+	log.Println(product, request)
+
 	if err != nil {
-		log.Println(err)
-		return errors.New("Error while decoding params")
+		return nil, err
 	}
 
-	shippingRates, err := printful.CalculateTaxRate(calculateTaxRateRequest)
+	return product, nil
+}
+
+func calculateShippingRates(c *gin.Context, request *apimodel.CalculateShippingRates) (any, error) {
+	shippingRates, err := printful.CalculateShippingRates(requestStore(c), *request)
 	log.Println(shippingRates, err)
 	if err != nil {
-		log.Println(err)
-		return errors.New("Error while calculating shipping rates")
+		return nil, apierrors.Wrap("Error while calculating shipping rates", err)
 	}
 
-	jsonSuccess(c, shippingRates)
-
-	return nil
+	return shippingRates, nil
 }
 
-func createOrder(c *gin.Context, params map[string]interface{}) error {
-	log.Println("<<<<<<<<<<<<<<<<<<<<<<", params)
-
-	createOrderRequest := requests.CreateOrder{}
-	err := mapstructure.Decode(params, &createOrderRequest)
+func calculateTaxRate(c *gin.Context, request *apimodel.CalculateTaxRate) (any, error) {
+	shippingRates, err := printful.CalculateTaxRate(requestStore(c), *request)
+	log.Println(shippingRates, err)
 	if err != nil {
-		log.Println(err)
-		return errors.New("Error while decoding params")
+		return nil, apierrors.Wrap("Error while calculating shipping rates", err)
 	}
 
-	log.Println("=====================", createOrderRequest)
+	return shippingRates, nil
+}
 
-	order, err := printful.CreateOrder(createOrderRequest)
-	log.Println(order, err)
+func createOrder(c *gin.Context, request *apimodel.CreateOrder) (any, error) {
+	refund, err := consumeQuota(c, quotaOrders, 1)
+	if err != nil {
+		return nil, err
+	}
 
-	jsonSuccess(c, map[string]interface{}{
-		"order": order,
-	})
+	order, err := printful.CreateOrder(requestStore(c), *request)
+	if err != nil {
+		refund(1)
+		return nil, apierrors.Wrap("Error while creating order", err)
+	}
 
-	return nil
+	return map[string]interface{}{
+		"order": order,
+	}, nil
 }
 
-func addImages(c *gin.Context, params map[string]interface{}) error {
-	addImageRequest := removeme.AddImagesRequest{}
-	err := mapstructure.Decode(params, &addImageRequest)
+func addImages(c *gin.Context, request *requests.AddImagesRequest) (any, error) {
+	var size int64
+	for _, image := range request.Images {
+		size += imageDataSize(image)
+	}
+	refund, err := consumeQuota(c, quotaUploadBytes, size)
 	if err != nil {
-		log.Println(err)
-		return errors.New("Error while decoding params")
+		return nil, err
 	}
 
-	imageURLS := make([]string, len(addImageRequest.Images))
-	thumbURLS := make([]string, len(addImageRequest.Images))
+	imagesURL, err := storeImagesURL(c)
+	if err != nil {
+		refund(size)
+		return nil, err
+	}
+
+	imageURLS := make([]string, len(request.Images))
+	thumbURLS := make([]string, len(request.Images))
 
-	for i, image := range addImageRequest.Images {
-		image, thumb, err := addImage(image)
+	for i, image := range request.Images {
+		image, thumb, err := addImage(image, imagesURL)
 		if err != nil {
-			return err
+			// Images already stored are not refunded
+			refund(size)
+			return nil, err
 		}
 		imageURLS[i] = image
 		thumbURLS[i] = thumb
+		size -= imageDataSize(request.Images[i])
 	}
 
-	jsonSuccess(c, map[string]interface{}{
+	return map[string]interface{}{
 		"image_urls": imageURLS,
 		"thumb_urls": thumbURLS,
-	})
-	return nil
+	}, nil
+}
+
+// Size of a base64 image once decoded
+func imageDataSize(data string) int64 {
+	b64data := data[strings.IndexByte(data, ',')+1:]
+	return int64(base64.StdEncoding.DecodedLen(len(b64data)))
 }
 
-func addImage(data string) (string, string, error) {
+func addImage(data string, imagesURL string) (string, string, error) {
 	b64data := data[strings.IndexByte(data, ',')+1:] // Remove data:image/png;base64,
 
 	reader := base64.NewDecoder(base64.StdEncoding, strings.NewReader(b64data))
 	config, err := png.DecodeConfig(reader)
 	if err != nil {
-		return "", "", errors.New("Error while decoding image")
+		return "", "", apierrors.Validation("Error while decoding image", err)
 	}
 
 	if config.Width > 20000 || config.Height > 20000 {
-		return "", "", errors.New("image too large")
+		return "", "", apierrors.Validation("image too large", nil)
 	}
 
 	img, err := png.Decode(base64.NewDecoder(base64.StdEncoding, strings.NewReader(b64data)))
 	if err != nil {
-		return "", "", errors.New("Error while decoding image")
+		return "", "", apierrors.Validation("Error while decoding image", err)
 	}
 
 	newWidth, newHeight := 200, 200
@@ -453,12 +660,12 @@ func addImage(data string) (string, string, error) {
 		return "", "", errors.New("failed to save thumbnail")
 	}
 
-	imageURL, err := url.JoinPath(apiConfig.ImagesURL, "/", filename)
+	imageURL, err := url.JoinPath(imagesURL, "/", filename)
 	if err != nil {
 		return "", "", errors.New("unable to create image url")
 	}
 
-	thumbnailURL, err := url.JoinPath(apiConfig.ImagesURL, "/", filename+"_thumb")
+	thumbnailURL, err := url.JoinPath(imagesURL, "/", filename+"_thumb")
 	if err != nil {
 		return "", "", errors.New("unable to create thumbnail url")
 	}
@@ -0,0 +1,260 @@
+package api
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/subtle"
+	"database/sql"
+	"encoding/hex"
+	"errors"
+	"go-printful-api/src/apierrors"
+	"go-printful-api/src/database"
+	"go-printful-api/src/model/requests"
+	"go-printful-api/src/printful"
+	"log"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+const (
+	ScopeCatalogRead = "catalog:read"
+	ScopeImagesWrite = "images:write"
+	ScopeOrdersWrite = "orders:write"
+	// Grants every scope
+	ScopeAdmin = "admin"
+)
+
+// Scope required by each action. Actions not listed here require the admin scope
+var actionScopes = map[string]string{
+	"get-categories":           ScopeCatalogRead,
+	"get-countries":            ScopeCatalogRead,
+	"get-products":             ScopeCatalogRead,
+	"search-products":          ScopeCatalogRead,
+	"get-product":              ScopeCatalogRead,
+	"get-product-prices":       ScopeCatalogRead,
+	"get-price-history":        ScopeCatalogRead,
+	"get-price-changes":        ScopeCatalogRead,
+	"get-catalog-changes":      ScopeCatalogRead,
+	"get-variant":              ScopeCatalogRead,
+	"get-available-variants":   ScopeCatalogRead,
+	"get-similar-variants":     ScopeCatalogRead,
+	"get-mockup-templates":     ScopeCatalogRead,
+	"get-mockup-styles":        ScopeCatalogRead,
+	"get-product-images":       ScopeCatalogRead,
+	"create-sync-product":      ScopeImagesWrite,
+	"add-images":               ScopeImagesWrite,
+	"get-sync-product":         ScopeOrdersWrite,
+	"calculate-shipping-rates": ScopeOrdersWrite,
+	"calculate-tax-rate":       ScopeOrdersWrite,
+	"create-order":             ScopeOrdersWrite,
+}
+
+func actionScope(action string) string {
+	if scope, found := actionScopes[action]; found {
+		return scope
+	}
+	return ScopeAdmin
+}
+
+const apiKeyPrefix = "pfa_"
+const clientContextKey = "api_client"
+
+// Caller of a request. key is nil for anonymous clients and for the admin key of the config
+type client struct {
+	// Identifies the client in rate limits and quotas: the id of the API key or the IP of anonymous clients
+	id     string
+	key    *database.ApiKey
+	scopes []string
+}
+
+func (cl *client) hasScope(scope string) bool {
+	return slices.Contains(cl.scopes, ScopeAdmin) || slices.Contains(cl.scopes, scope)
+}
+
+func hashApiKey(key string) string {
+	hash := sha256.Sum256([]byte(key))
+	return hex.EncodeToString(hash[:])
+}
+
+// The key is read from the X-Api-Key header or from a bearer token
+func requestApiKey(c *gin.Context) string {
+	if key := c.GetHeader("X-Api-Key"); key != "" {
+		return key
+	}
+
+	if token, found := strings.CutPrefix(c.GetHeader("Authorization"), "Bearer "); found {
+		return strings.TrimSpace(token)
+	}
+
+	return ""
+}
+
+// Identify the client of a request. The client is computed once per request
+func authenticate(c *gin.Context) (*client, error) {
+	if value, found := c.Get(clientContextKey); found {
+		return value.(*client), nil
+	}
+
+	cl, err := findClient(c)
+	if err != nil {
+		return nil, err
+	}
+
+	c.Set(clientContextKey, cl)
+	return cl, nil
+}
+
+func findClient(c *gin.Context) (*client, error) {
+	key := requestApiKey(c)
+	if key == "" {
+		scopes := apiConfig.AnonymousScopes
+		if scopes == nil {
+			scopes = []string{ScopeCatalogRead}
+		}
+		return &client{id: "ip:" + c.ClientIP(), scopes: scopes}, nil
+	}
+
+	if apiConfig.AdminKey != "" && subtle.ConstantTimeCompare([]byte(key), []byte(apiConfig.AdminKey)) == 1 {
+		return &client{id: "admin", scopes: []string{ScopeAdmin}}, nil
+	}
+
+	apiKey, err := database.FindApiKeyByHash(hashApiKey(key))
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, apierrors.Unauthorized("invalid API key")
+		}
+		return nil, apierrors.Wrap("Error while checking API key", err)
+	}
+
+	if apiKey.Revoked {
+		return nil, apierrors.Unauthorized("API key has been revoked")
+	}
+
+	return &client{id: "key:" + strconv.Itoa(apiKey.ID), key: &apiKey, scopes: apiKey.Scopes}, nil
+}
+
+// Check the client is allowed to call an action from the origin of the request
+func authorize(c *gin.Context, action string) error {
+	cl, err := authenticate(c)
+	if err != nil {
+		return err
+	}
+
+	scope := actionScope(action)
+	if !cl.hasScope(scope) {
+		if requestApiKey(c) == "" {
+			return apierrors.Unauthorized("an API key with scope " + scope + " is required")
+		}
+		return apierrors.Forbidden("API key is missing scope " + scope)
+	}
+
+	if origin := c.GetHeader("Origin"); origin != "" && !cl.allowsOrigin(origin) {
+		return apierrors.Forbidden("origin " + origin + " is not allowed")
+	}
+
+	return nil
+}
+
+// Requests sent by browsers must come from an origin allowed by the config or by the API key
+func (cl *client) allowsOrigin(origin string) bool {
+	if slices.Contains(apiConfig.AllowedOrigins, origin) {
+		return true
+	}
+	if cl.key == nil {
+		// The admin key of the config is not bound to any origin
+		return slices.Contains(cl.scopes, ScopeAdmin)
+	}
+	return slices.Contains(cl.key.AllowedOrigins, origin)
+}
+
+// Used by the CORS middleware: an origin is allowed if the config or an active API key allows it
+func AllowedOrigin(origin string) bool {
+	if slices.Contains(apiConfig.AllowedOrigins, origin) {
+		return true
+	}
+
+	keys, err := database.FindApiKeys()
+	if err != nil {
+		log.Println("error in AllowedOrigin:", err)
+		return false
+	}
+
+	for _, key := range keys {
+		if !key.Revoked && slices.Contains(key.AllowedOrigins, origin) {
+			return true
+		}
+	}
+
+	return false
+}
+
+func createApiKey(c *gin.Context, request *requests.CreateApiKeyRequest) (any, error) {
+	random := make([]byte, 32)
+	if _, err := rand.Read(random); err != nil {
+		return nil, apierrors.Internal("Error while generating API key", err)
+	}
+	key := apiKeyPrefix + hex.EncodeToString(random)
+
+	if request.StoreID != "" && !printful.HasStore(request.StoreID) {
+		return nil, invalidParams(RequestError{Fields: []FieldError{{Field: "store_id", Message: "must be an existing store"}}})
+	}
+
+	// Orders and sync products are only available to keys bound to a store
+	if request.StoreID == "" && (slices.Contains(request.Scopes, ScopeOrdersWrite) || slices.Contains(request.Scopes, ScopeImagesWrite)) {
+		return nil, invalidParams(RequestError{Fields: []FieldError{{Field: "store_id", Message: "is required for scopes " + ScopeOrdersWrite + " and " + ScopeImagesWrite}}})
+	}
+
+	allowedOrigins := request.AllowedOrigins
+	if allowedOrigins == nil {
+		allowedOrigins = []string{}
+	}
+
+	apiKey := database.ApiKey{
+		Name:           request.Name,
+		KeyHash:        hashApiKey(key),
+		StoreID:        request.StoreID,
+		Scopes:         request.Scopes,
+		AllowedOrigins: allowedOrigins,
+		DateCreated:    time.Now().Unix(),
+	}
+
+	id, err := database.InsertApiKey(&apiKey)
+	if err != nil {
+		return nil, apierrors.Wrap("Error while creating API key", err)
+	}
+	apiKey.ID = id
+
+	// The key can't be retrieved later, only its hash is stored
+	return map[string]interface{}{
+		"key":     key,
+		"api_key": apiKey,
+	}, nil
+}
+
+func revokeApiKey(c *gin.Context, request *requests.RevokeApiKeyRequest) (any, error) {
+	if err := database.RevokeApiKey(request.ID); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, apierrors.NotFound("API key "+strconv.Itoa(request.ID)+" not found", err)
+		}
+		return nil, apierrors.Wrap("Error while revoking API key", err)
+	}
+
+	return map[string]interface{}{
+		"revoked": true,
+	}, nil
+}
+
+func getApiKeys(c *gin.Context, request *requests.EmptyRequest) (any, error) {
+	keys, err := database.FindApiKeys()
+	if err != nil {
+		return nil, apierrors.Wrap("Error while getting API keys", err)
+	}
+
+	return map[string]interface{}{
+		"keys": keys,
+	}, nil
+}
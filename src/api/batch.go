@@ -0,0 +1,87 @@
+package api
+
+import (
+	"bytes"
+	"encoding/json"
+	"go-printful-api/src/apierrors"
+	"net/http"
+	"slices"
+	"strconv"
+	"sync"
+
+	"github.com/gin-gonic/gin"
+)
+
+const defaultBatchMaxSize = 20
+const defaultBatchConcurrency = 4
+
+func isBatch(body []byte) bool {
+	return bytes.HasPrefix(bytes.TrimSpace(body), []byte("["))
+}
+
+// Run the requests of a batch concurrently. Results are returned in the order of the requests, each in its own envelope
+func batchHandler(c *gin.Context, body []byte) {
+	var batch []json.RawMessage
+	if err := json.Unmarshal(body, &batch); err != nil {
+		jsonError(c, apierrors.Validation("bad request", err))
+		return
+	}
+
+	maxSize := apiConfig.BatchMaxSize
+	if maxSize <= 0 {
+		maxSize = defaultBatchMaxSize
+	}
+	if len(batch) > maxSize {
+		jsonError(c, apierrors.Validation("batch too large, maximum size is "+strconv.Itoa(maxSize), nil))
+		return
+	}
+
+	concurrency := apiConfig.BatchConcurrency
+	if concurrency <= 0 {
+		concurrency = defaultBatchConcurrency
+	}
+
+	results := make([]gin.H, len(batch))
+	retryAfters := make([]int, len(batch))
+	semaphore := make(chan struct{}, concurrency)
+	var wg sync.WaitGroup
+
+	for i, data := range batch {
+		wg.Add(1)
+		semaphore <- struct{}{}
+		go func() {
+			defer wg.Done()
+			defer func() { <-semaphore }()
+
+			results[i], retryAfters[i] = runBatchRequest(c, data)
+		}()
+	}
+	wg.Wait()
+
+	// Rate limited requests report retry_after in their envelope, the header holds the longest wait
+	if retryAfter := slices.Max(append(retryAfters, 0)); retryAfter > 0 {
+		c.Header("Retry-After", strconv.Itoa(retryAfter))
+	}
+
+	c.JSON(http.StatusOK, results)
+}
+
+// Returns the envelope of a request and the seconds to wait before retrying it, 0 if it was not rate limited
+func runBatchRequest(c *gin.Context, data json.RawMessage) (gin.H, int) {
+	var request ApiRequest
+	if err := decodeApiRequest(data, &request); err != nil {
+		body, _ := errorBody(apierrors.Validation("bad request", err))
+		return errorEnvelope(body), 0
+	}
+
+	result, err := runAction(c, &request)
+	if err != nil {
+		body, apiError := errorBody(err)
+		if apiError.Kind == apierrors.KindRateLimited {
+			return errorEnvelope(body), apiError.RetryAfter
+		}
+		return errorEnvelope(body), 0
+	}
+
+	return successEnvelope(result), 0
+}
@@ -1,7 +1,23 @@
 package api
 
-type NotFoundError struct{}
+import (
+	"strings"
+)
 
-func (e NotFoundError) Error() string {
-	return "Not found"
+type FieldError struct {
+	Field   string `json:"field"`
+	Message string `json:"message"`
+}
+
+// Returned when the params of a request can't be decoded or are invalid
+type RequestError struct {
+	Fields []FieldError
+}
+
+func (e RequestError) Error() string {
+	messages := make([]string, 0, len(e.Fields))
+	for _, field := range e.Fields {
+		messages = append(messages, field.Field+" "+field.Message)
+	}
+	return "Invalid params: " + strings.Join(messages, ", ")
 }
@@ -1,7 +1,7 @@
 package api
 
 import (
-	"errors"
+	"go-printful-api/src/apierrors"
 	"go-printful-api/src/database"
 	"log"
 	"net/http"
@@ -14,9 +14,10 @@ func ImageHandler(c *gin.Context) {
 
 	img, err := database.GetImage(c.Param("id"))
 	if err != nil {
-		jsonError(c, errors.New("failed to read image"))
+		jsonError(c, apierrors.Wrap("failed to read image", err))
 		return
 	}
 
-	c.Data(http.StatusOK, "image/png", img)
+	// Mirrored Printful images are stored in their original format
+	c.Data(http.StatusOK, http.DetectContentType(img), img)
 }
@@ -1,24 +1,74 @@
 package api
 
 import (
+	"errors"
+	"go-printful-api/src/apierrors"
+	"log"
 	"net/http"
+	"strconv"
 
 	"github.com/gin-gonic/gin"
 )
 
 func jsonError(c *gin.Context, e error) {
-	c.JSON(http.StatusOK, gin.H{
+	body, apiError := errorBody(e)
+
+	status := http.StatusOK
+	if apiConfig.HTTPStatusCodes {
+		status = apiError.HTTPStatus()
+	}
+
+	if apiError.Kind == apierrors.KindRateLimited && apiError.RetryAfter > 0 {
+		c.Header("Retry-After", strconv.Itoa(apiError.RetryAfter))
+	}
+
+	c.JSON(status, errorEnvelope(body))
+}
+
+func errorBody(e error) (gin.H, *apierrors.Error) {
+	apiError := apierrors.Classify(e)
+	if apiError.Kind == apierrors.KindInternal {
+		log.Println(e)
+	}
+
+	body := gin.H{
+		"code":    apiError.Code(),
+		"message": apiError.Message,
+	}
+
+	var requestError RequestError
+	if errors.As(e, &requestError) {
+		body["fields"] = requestError.Fields
+	}
+
+	if apiError.Kind == apierrors.KindUpstream {
+		body["upstream"] = gin.H{
+			"status":  apiError.UpstreamStatus,
+			"message": apiError.UpstreamMessage,
+		}
+	}
+
+	if apiError.Kind == apierrors.KindRateLimited && apiError.RetryAfter > 0 {
+		body["retry_after"] = apiError.RetryAfter
+	}
+
+	return body, apiError
+}
+
+func errorEnvelope(body gin.H) gin.H {
+	return gin.H{
 		"success": false,
-		"error": gin.H{
-			"code":    0,
-			"message": e.Error(),
-		},
-	})
+		"error":   body,
+	}
 }
 
-func jsonSuccess(c *gin.Context, data interface{}) {
-	c.JSON(http.StatusOK, gin.H{
+func successEnvelope(data interface{}) gin.H {
+	return gin.H{
 		"success": true,
 		"result":  data,
-	})
+	}
+}
+
+func jsonSuccess(c *gin.Context, data interface{}) {
+	c.JSON(http.StatusOK, successEnvelope(data))
 }
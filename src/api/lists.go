@@ -0,0 +1,123 @@
+package api
+
+import (
+	"cmp"
+	"encoding/base64"
+	"encoding/json"
+	"go-printful-api/src/apierrors"
+	"go-printful-api/src/model/requests"
+	"slices"
+)
+
+const maxListLimit = 500
+
+// Pagination is enabled when a cursor or a limit is provided
+func paginated(p requests.ListRequest) bool {
+	return p.Cursor != "" || p.Limit > 0
+}
+
+// Returns the items following the cursor, sorted by key, and the cursor of the next page. The next cursor is empty on the last page
+func paginate[T any, K cmp.Ordered](items []T, key func(T) K, p requests.ListRequest) ([]T, string, error) {
+	sorted := slices.Clone(items)
+	slices.SortFunc(sorted, func(a, b T) int { return cmp.Compare(key(a), key(b)) })
+
+	start := 0
+	if p.Cursor != "" {
+		after, err := decodeCursor[K](p.Cursor)
+		if err != nil {
+			return nil, "", apierrors.Validation("Error while decoding param cursor", err)
+		}
+		start, _ = slices.BinarySearchFunc(sorted, after, func(item T, k K) int { return cmp.Compare(key(item), k) })
+		if start < len(sorted) && key(sorted[start]) == after {
+			start++
+		}
+	}
+
+	limit := min(p.Limit, maxListLimit)
+	if limit <= 0 {
+		limit = maxListLimit
+	}
+	end := min(start+limit, len(sorted))
+
+	page := sorted[start:end]
+	if end == len(sorted) {
+		return page, "", nil
+	}
+
+	next, err := encodeCursor(key(page[len(page)-1]))
+	if err != nil {
+		return nil, "", err
+	}
+
+	return page, next, nil
+}
+
+func encodeCursor[K any](key K) (string, error) {
+	j, err := json.Marshal(key)
+	if err != nil {
+		return "", err
+	}
+	return base64.RawURLEncoding.EncodeToString(j), nil
+}
+
+func decodeCursor[K any](cursor string) (K, error) {
+	var key K
+	j, err := base64.RawURLEncoding.DecodeString(cursor)
+	if err != nil {
+		return key, err
+	}
+	err = json.Unmarshal(j, &key)
+	return key, err
+}
+
+// Keep only the requested fields of each item. Items are returned unchanged if no field is requested
+func project[T any](items []T, fields []string) (any, error) {
+	if len(fields) == 0 {
+		return items, nil
+	}
+
+	projected := make([]map[string]json.RawMessage, 0, len(items))
+	for _, item := range items {
+		j, err := json.Marshal(item)
+		if err != nil {
+			return nil, err
+		}
+
+		all := make(map[string]json.RawMessage)
+		if err = json.Unmarshal(j, &all); err != nil {
+			return nil, err
+		}
+
+		selected := make(map[string]json.RawMessage, len(fields))
+		for _, field := range fields {
+			if value, found := all[field]; found {
+				selected[field] = value
+			}
+		}
+		projected = append(projected, selected)
+	}
+
+	return projected, nil
+}
+
+// Apply pagination and projection to a list. Unpaginated lists are returned as is for backward compatibility
+func listResult[T any, K cmp.Ordered](items []T, key func(T) K, p requests.ListRequest) (any, error) {
+	if !paginated(p) {
+		return project(items, p.Fields)
+	}
+
+	page, next, err := paginate(items, key, p)
+	if err != nil {
+		return nil, err
+	}
+
+	projected, err := project(page, p.Fields)
+	if err != nil {
+		return nil, err
+	}
+
+	return map[string]interface{}{
+		"items":       projected,
+		"next_cursor": next,
+	}, nil
+}
@@ -0,0 +1,497 @@
+package api
+
+import (
+	"encoding/json"
+	"errors"
+	"go-printful-api/src/apierrors"
+	"go-printful-api/src/database"
+	"go-printful-api/src/printful"
+	"net/http"
+	"path"
+	"reflect"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	printfulsdk "github.com/baldurstod/go-printful-sdk"
+	printfulmodel "github.com/baldurstod/go-printful-sdk/model"
+	printfulAPIModel "github.com/baldurstod/printful-api-model"
+	"github.com/baldurstod/printful-api-model/schemas"
+	"github.com/gin-gonic/gin"
+)
+
+// Description of the response of an action version
+type actionDoc struct {
+	summary  string
+	response reflect.Type
+	// Paginated actions return a page instead of the full list when a cursor or a limit is provided
+	paginated bool
+}
+
+// Paginated requests get the page in items instead of categories
+type categoriesResponse struct {
+	Categories []printfulmodel.Category `json:"categories,omitempty"`
+	Items      []printfulmodel.Category `json:"items,omitempty"`
+	NextCursor string                   `json:"next_cursor,omitempty"`
+	Tree       []*printful.CategoryNode `json:"tree"`
+}
+
+type productResponse struct {
+	Product     printfulmodel.Product        `json:"product"`
+	Variants    []printfulmodel.Variant      `json:"variants"`
+	Translation *database.ProductTranslation `json:"translation"`
+	Images      []database.TaggedImage       `json:"images"`
+}
+
+type productV2Response struct {
+	Product             printfulmodel.Product   `json:"product"`
+	Variants            []printfulmodel.Variant `json:"variants"`
+	Images              []database.TaggedImage  `json:"images"`
+	AvailableVariantIDs []int                   `json:"available_variant_ids,omitempty"`
+}
+
+type priceHistoryResponse struct {
+	History []database.PriceHistory `json:"history"`
+}
+
+type priceChangesResponse struct {
+	Changes []database.PriceChange `json:"changes"`
+}
+
+type catalogChangesResponse struct {
+	Changes []database.CatalogChange `json:"changes"`
+}
+
+type retryFailedResponse struct {
+	Started bool `json:"started"`
+}
+
+type cancelRefreshResponse struct {
+	Cancelled bool `json:"cancelled"`
+}
+
+type refreshProductResponse struct {
+	ProductID int `json:"product_id"`
+}
+
+type cacheStatsResponse struct {
+	Caches []database.CacheStats `json:"caches"`
+}
+
+type variantsResponse struct {
+	Variants []printfulmodel.Variant `json:"variants"`
+}
+
+type templatesResponse struct {
+	Templates []printfulmodel.MockupTemplates `json:"templates"`
+}
+
+type stylesResponse struct {
+	Styles []printfulmodel.MockupStyles `json:"styles"`
+}
+
+type productImagesResponse struct {
+	Images []printfulmodel.VariantImages `json:"images"`
+}
+
+type orderResponse struct {
+	Order printfulmodel.Order `json:"order"`
+}
+
+type addImagesResponse struct {
+	ImageURLs []string `json:"image_urls"`
+	ThumbURLs []string `json:"thumb_urls"`
+}
+
+type createApiKeyResponse struct {
+	Key    string          `json:"key"`
+	ApiKey database.ApiKey `json:"api_key"`
+}
+
+type apiKeysResponse struct {
+	Keys []database.ApiKey `json:"keys"`
+}
+
+type revokeApiKeyResponse struct {
+	Revoked bool `json:"revoked"`
+}
+
+// Every version of every action must be documented here
+var actionDocs = map[string]map[int]actionDoc{
+	"get-categories":           {1: {summary: "List the categories", response: reflect.TypeFor[[]printfulmodel.Category](), paginated: true}, 2: {summary: "List the categories and their tree", response: reflect.TypeFor[categoriesResponse]()}},
+	"get-countries":            {1: {summary: "List the countries", response: reflect.TypeFor[[]printfulmodel.Country](), paginated: true}},
+	"get-products":             {1: {summary: "List the products", response: reflect.TypeFor[[]printfulmodel.Product](), paginated: true}},
+	"search-products":          {1: {summary: "Search products", response: reflect.TypeFor[printful.SearchProductsResult]()}},
+	"get-product":              {1: {summary: "Get a product and its variants", response: reflect.TypeFor[productResponse]()}, 2: {summary: "Get a localized product, its variants and their availability", response: reflect.TypeFor[productV2Response]()}},
+	"get-product-prices":       {1: {summary: "Get the prices of a product", response: reflect.TypeFor[printfulmodel.ProductPrices]()}},
+	"get-price-history":        {1: {summary: "Get the price history of a product", response: reflect.TypeFor[priceHistoryResponse]()}},
+	"get-price-changes":        {1: {summary: "List recent price changes", response: reflect.TypeFor[priceChangesResponse]()}},
+	"get-catalog-changes":      {1: {summary: "List catalog changes", response: reflect.TypeFor[catalogChangesResponse]()}},
+	"get-refresh-progress":     {1: {summary: "Get the progress of the current refresh", response: reflect.TypeFor[printful.RefreshProgress]()}},
+	"get-refresh-status":       {1: {summary: "Get the status of a refresh run", response: reflect.TypeFor[printful.RefreshStatus]()}},
+	"retry-failed":             {1: {summary: "Retry the failed steps of a refresh run", response: reflect.TypeFor[retryFailedResponse]()}},
+	"cancel-refresh":           {1: {summary: "Cancel the running refresh or retry", response: reflect.TypeFor[cancelRefreshResponse]()}},
+	"refresh-product":          {1: {summary: "Refresh a product from Printful", response: reflect.TypeFor[refreshProductResponse]()}},
+	"get-cache-stats":          {1: {summary: "Get the statistics of the memory caches", response: reflect.TypeFor[cacheStatsResponse]()}},
+	"get-variant":              {1: {summary: "Get a variant", response: reflect.TypeFor[printfulmodel.Variant]()}},
+	"get-available-variants":   {1: {summary: "List the variants of a product available in a country", response: reflect.TypeFor[variantsResponse]()}},
+	"get-similar-variants":     {1: {summary: "List the variants sharing the same placements", response: reflect.TypeFor[[]int]()}},
+	"get-mockup-templates":     {1: {summary: "Get the mockup templates of a product", response: reflect.TypeFor[templatesResponse]()}},
+	"get-mockup-styles":        {1: {summary: "Get the mockup styles of a product", response: reflect.TypeFor[stylesResponse]()}},
+	"get-product-images":       {1: {summary: "Get the images of a product", response: reflect.TypeFor[productImagesResponse]()}},
+	"create-sync-product":      {1: {summary: "Create a sync product", response: reflect.TypeFor[schemas.SyncProduct]()}},
+	"get-sync-product":         {1: {summary: "Get a sync product", response: reflect.TypeFor[printfulAPIModel.SyncProductInfo]()}},
+	"calculate-shipping-rates": {1: {summary: "Calculate shipping rates", response: reflect.TypeFor[[]printfulmodel.ShippingRate]()}},
+	"calculate-tax-rate":       {1: {summary: "Calculate the tax rate", response: reflect.TypeFor[schemas.TaxInfo]()}},
+	"create-order":             {1: {summary: "Create an order", response: reflect.TypeFor[orderResponse]()}},
+	"add-images":               {1: {summary: "Upload images", response: reflect.TypeFor[addImagesResponse]()}},
+	"create-api-key":           {1: {summary: "Create an API key. The key is only returned once", response: reflect.TypeFor[createApiKeyResponse]()}},
+	"revoke-api-key":           {1: {summary: "Revoke an API key", response: reflect.TypeFor[revokeApiKeyResponse]()}},
+	"get-api-keys":             {1: {summary: "List the API keys", response: reflect.TypeFor[apiKeysResponse]()}},
+}
+
+func OpenAPIHandler(c *gin.Context) {
+	document, err := OpenAPIDocument()
+	if err != nil {
+		jsonError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, document)
+}
+
+// Generate the OpenAPI document of the /api endpoint from the action registry
+func OpenAPIDocument() (map[string]any, error) {
+	requestGenerator := newSchemaGenerator("mapstructure", ".params")
+	responseGenerator := newSchemaGenerator("json", "")
+
+	names := make([]string, 0, len(actions))
+	for name := range actions {
+		names = append(names, name)
+	}
+	slices.Sort(names)
+
+	requestSchemas := make([]any, 0)
+	responseSchemas := make([]any, 0)
+	for _, name := range names {
+		versions := actions[name]
+		numbers := make([]int, 0, len(versions))
+		for version := range versions {
+			numbers = append(numbers, version)
+		}
+		slices.Sort(numbers)
+
+		for _, version := range numbers {
+			doc, found := actionDocs[name][version]
+			if !found {
+				return nil, apierrors.Internal("missing documentation for version "+strconv.Itoa(version)+" of action "+name, nil)
+			}
+
+			id := name + ".v" + strconv.Itoa(version)
+			requestGenerator.components[id+".request"] = map[string]any{
+				"type":        "object",
+				"title":       id,
+				"description": doc.summary,
+				"required":    []string{"action", "version"},
+				"properties": map[string]any{
+					"action":  map[string]any{"type": "string", "enum": []string{name}},
+					"version": map[string]any{"type": "integer", "enum": []int{version}},
+					"params":  requestGenerator.schema(versions[version].requestType()),
+					"store":   map[string]any{"type": "string", "description": "Printful store used by the action, the default store if not set"},
+				},
+			}
+			requestSchemas = append(requestSchemas, componentRef(id+".request"))
+
+			result := responseGenerator.schema(doc.response)
+			if doc.paginated {
+				result = map[string]any{
+					"oneOf": []any{
+						result,
+						map[string]any{
+							"type": "object",
+							"properties": map[string]any{
+								"items":       result,
+								"next_cursor": map[string]any{"type": "string", "description": "Empty on the last page"},
+							},
+						},
+					},
+				}
+			}
+
+			responseGenerator.components[id+".response"] = map[string]any{
+				"type":        "object",
+				"title":       id,
+				"description": doc.summary,
+				"properties": map[string]any{
+					"success": map[string]any{"type": "boolean", "enum": []bool{true}},
+					"result":  result,
+				},
+			}
+			responseSchemas = append(responseSchemas, componentRef(id+".response"))
+		}
+	}
+
+	for name := range actionDocs {
+		if _, found := actions[name]; !found {
+			return nil, errors.New("documentation of unknown action " + name)
+		}
+	}
+
+	components := requestGenerator.components
+	for name, schema := range responseGenerator.components {
+		components[name] = schema
+	}
+	components["error"] = errorSchema()
+	responseSchemas = append(responseSchemas, componentRef("error"))
+
+	request := map[string]any{"oneOf": requestSchemas}
+	response := map[string]any{"oneOf": responseSchemas}
+
+	return map[string]any{
+		"openapi": "3.0.3",
+		"info": map[string]any{
+			"title":   "go-printful-api",
+			"version": "1.0.0",
+		},
+		"paths": map[string]any{
+			"/api": map[string]any{
+				"post": map[string]any{
+					"summary": "Run an action, or a batch of actions when the body is an array",
+					"requestBody": map[string]any{
+						"required": true,
+						"content": map[string]any{
+							"application/json": map[string]any{
+								"schema": map[string]any{
+									"oneOf": []any{request, map[string]any{"type": "array", "items": request}},
+								},
+							},
+						},
+					},
+					"responses": map[string]any{
+						"200": map[string]any{
+							"description": "Result of the action, or the results of the batch in order",
+							"content": map[string]any{
+								"application/json": map[string]any{
+									"schema": map[string]any{
+										"oneOf": []any{response, map[string]any{"type": "array", "items": response}},
+									},
+								},
+							},
+						},
+					},
+				},
+			},
+		},
+		"components": map[string]any{
+			"schemas": components,
+		},
+	}, nil
+}
+
+func errorSchema() map[string]any {
+	return map[string]any{
+		"type": "object",
+		"properties": map[string]any{
+			"success": map[string]any{"type": "boolean", "enum": []bool{false}},
+			"error": map[string]any{
+				"type": "object",
+				"properties": map[string]any{
+					"code": map[string]any{
+						"type":        "integer",
+						"enum":        []int{apierrors.CodeInternal, apierrors.CodeValidation, apierrors.CodeNotFound, apierrors.CodeUpstream, apierrors.CodeRateLimited, apierrors.CodeUnauthorized, apierrors.CodeForbidden},
+						"description": "1000: internal error, 1001: validation error, 1002: not found, 1003: Printful error, 1004: rate limited, 1005: missing or invalid API key, 1006: API key not allowed",
+					},
+					"message": map[string]any{"type": "string"},
+					"fields": map[string]any{
+						"type":        "array",
+						"description": "Invalid params, for validation errors",
+						"items": map[string]any{
+							"type": "object",
+							"properties": map[string]any{
+								"field":   map[string]any{"type": "string"},
+								"message": map[string]any{"type": "string"},
+							},
+						},
+					},
+					"upstream": map[string]any{
+						"type":        "object",
+						"description": "Error returned by Printful, for upstream errors",
+						"properties": map[string]any{
+							"status":  map[string]any{"type": "integer"},
+							"message": map[string]any{"type": "string"},
+						},
+					},
+					"retry_after": map[string]any{"type": "integer", "description": "Seconds to wait before retrying, for rate limited errors"},
+				},
+			},
+		},
+	}
+}
+
+func componentRef(name string) map[string]any {
+	return map[string]any{"$ref": "#/components/schemas/" + name}
+}
+
+// Build JSON schemas from Go types. Field names are read from tagName, named structs are stored as components
+type schemaGenerator struct {
+	tagName    string
+	suffix     string
+	components map[string]any
+}
+
+func newSchemaGenerator(tagName string, suffix string) *schemaGenerator {
+	return &schemaGenerator{
+		tagName:    tagName,
+		suffix:     suffix,
+		components: make(map[string]any),
+	}
+}
+
+func (g *schemaGenerator) schema(t reflect.Type) map[string]any {
+	if t == reflect.TypeFor[time.Time]() {
+		return map[string]any{"type": "string", "format": "date-time"}
+	}
+	if t == reflect.TypeFor[json.RawMessage]() {
+		return map[string]any{}
+	}
+
+	switch t.Kind() {
+	case reflect.Pointer:
+		return g.schema(t.Elem())
+	case reflect.Bool:
+		return map[string]any{"type": "boolean"}
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64, reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return map[string]any{"type": "integer"}
+	case reflect.Float32, reflect.Float64:
+		return map[string]any{"type": "number"}
+	case reflect.String:
+		return map[string]any{"type": "string"}
+	case reflect.Slice, reflect.Array:
+		if t.Elem().Kind() == reflect.Uint8 {
+			return map[string]any{"type": "string", "format": "byte"}
+		}
+		return map[string]any{"type": "array", "items": g.schema(t.Elem())}
+	case reflect.Map:
+		return map[string]any{"type": "object", "additionalProperties": g.schema(t.Elem())}
+	case reflect.Struct:
+		if t.Name() == "" {
+			return g.object(t)
+		}
+
+		name := path.Base(t.PkgPath()) + "." + t.Name() + g.suffix
+		if _, found := g.components[name]; !found {
+			// Reserve the name first, for recursive types
+			g.components[name] = nil
+			g.components[name] = g.object(t)
+		}
+		return componentRef(name)
+	default:
+		return map[string]any{}
+	}
+}
+
+func (g *schemaGenerator) object(t reflect.Type) map[string]any {
+	properties := make(map[string]any)
+	required := make([]string, 0)
+	g.addProperties(t, properties, &required)
+
+	object := map[string]any{
+		"type":       "object",
+		"properties": properties,
+	}
+	if len(required) > 0 {
+		object["required"] = required
+	}
+	return object
+}
+
+func (g *schemaGenerator) addProperties(t reflect.Type, properties map[string]any, required *[]string) {
+	for i := range t.NumField() {
+		field := t.Field(i)
+		name, options, _ := strings.Cut(field.Tag.Get(g.tagName), ",")
+		if name == "-" {
+			continue
+		}
+
+		// Embedded structs are inlined when they are squashed or have no name
+		if field.Anonymous && (name == "" || strings.Contains(options, "squash")) {
+			fieldType := field.Type
+			if fieldType.Kind() == reflect.Pointer {
+				fieldType = fieldType.Elem()
+			}
+			if fieldType.Kind() == reflect.Struct {
+				g.addProperties(fieldType, properties, required)
+				continue
+			}
+		}
+
+		if !field.IsExported() {
+			continue
+		}
+		if name == "" {
+			name = field.Name
+		}
+
+		schema := g.schema(field.Type)
+		if g.applyConstraints(field, &schema) {
+			*required = append(*required, name)
+		}
+		properties[name] = schema
+	}
+}
+
+// Add the default value and validation rules of a field to its schema. Returns true if the field is required
+func (g *schemaGenerator) applyConstraints(field reflect.StructField, schema *map[string]any) bool {
+	s := make(map[string]any)
+	for k, v := range *schema {
+		s[k] = v
+	}
+	// Constraints can't be added next to a $ref
+	if ref, found := s["$ref"]; found {
+		s = map[string]any{"allOf": []any{map[string]any{"$ref": ref}}}
+	}
+
+	if def, found := field.Tag.Lookup("default"); found {
+		if n, err := strconv.Atoi(def); err == nil {
+			s["default"] = n
+		} else {
+			s["default"] = def
+		}
+	}
+
+	if field.Tag.Get("fallback") == "language" {
+		s["description"] = "One of " + strings.Join(printfulsdk.Languages, ", ") + ", other values fall back to the default"
+	}
+
+	isRequired := false
+	for _, rule := range strings.Split(field.Tag.Get("validate"), ",") {
+		name, param, _ := strings.Cut(rule, "=")
+		switch name {
+		case "dive":
+			// Following rules apply to the elements
+			*schema = s
+			return isRequired
+		case "required":
+			isRequired = true
+		case "gt":
+			s["minimum"], _ = strconv.Atoi(param)
+			s["exclusiveMinimum"] = true
+		case "gte":
+			s["minimum"], _ = strconv.Atoi(param)
+		case "min":
+			s["minItems"], _ = strconv.Atoi(param)
+		case "oneof":
+			s["enum"] = strings.Split(param, " ")
+		case "iso4217":
+			s["pattern"] = "^[A-Z]{3}$"
+		case "iso3166_1_alpha2":
+			s["pattern"] = "^[A-Z]{2}$"
+		}
+	}
+
+	if len(s) > 1 || s["allOf"] == nil {
+		*schema = s
+	}
+	return isRequired
+}
@@ -0,0 +1,173 @@
+package api
+
+import (
+	"go-printful-api/src/apierrors"
+	"go-printful-api/src/config"
+	"go-printful-api/src/database"
+	"log"
+	"math"
+	"slices"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+// Limits used when the config doesn't set one for an action class. Admin actions are not limited
+var defaultRateLimits = map[string]config.RateLimit{
+	ScopeCatalogRead: {Rate: 10, Burst: 50},
+	ScopeImagesWrite: {Rate: 0.5, Burst: 10},
+	ScopeOrdersWrite: {Rate: 0.2, Burst: 5},
+}
+
+const defaultDailyUploadBytes = 200 << 20
+const defaultDailyOrders = 50
+
+const (
+	quotaUploadBytes = "upload_bytes"
+	quotaOrders      = "orders"
+)
+
+// Idle buckets are removed past this number of buckets
+const maxIdleBuckets = 10000
+
+type tokenBucket struct {
+	tokens  float64
+	updated time.Time
+}
+
+type rateLimiter struct {
+	mutex   sync.Mutex
+	buckets map[string]*tokenBucket
+}
+
+var limiter = &rateLimiter{buckets: make(map[string]*tokenBucket)}
+
+// Take a token from a bucket. Returns the number of seconds before a token is available when the bucket is empty
+func (l *rateLimiter) take(key string, limit config.RateLimit) (int, bool) {
+	l.mutex.Lock()
+	defer l.mutex.Unlock()
+
+	now := time.Now()
+	burst := float64(max(limit.Burst, 1))
+
+	bucket, found := l.buckets[key]
+	if !found {
+		if len(l.buckets) >= maxIdleBuckets {
+			l.removeIdleBuckets(now)
+		}
+		bucket = &tokenBucket{tokens: burst, updated: now}
+		l.buckets[key] = bucket
+	}
+
+	bucket.tokens = min(burst, bucket.tokens+now.Sub(bucket.updated).Seconds()*limit.Rate)
+	bucket.updated = now
+
+	if bucket.tokens < 1 {
+		return int(math.Ceil((1 - bucket.tokens) / limit.Rate)), false
+	}
+
+	bucket.tokens--
+	return 0, true
+}
+
+// Buckets unused for an hour are full again with the usual limits and can be recreated
+func (l *rateLimiter) removeIdleBuckets(now time.Time) {
+	for key, bucket := range l.buckets {
+		if now.Sub(bucket.updated) > time.Hour {
+			delete(l.buckets, key)
+		}
+	}
+}
+
+func rateLimit(class string) (config.RateLimit, bool) {
+	if limit, found := apiConfig.RateLimits[class]; found && limit.Rate > 0 {
+		return limit, true
+	}
+	limit, found := defaultRateLimits[class]
+	return limit, found
+}
+
+// Apply the rate limit of the client for the class of an action
+func limitRate(c *gin.Context, action string) error {
+	cl, err := authenticate(c)
+	if err != nil {
+		return err
+	}
+
+	if cl.unlimited() {
+		return nil
+	}
+
+	class := actionScope(action)
+	limit, found := rateLimit(class)
+	if !found {
+		return nil
+	}
+
+	if retryAfter, ok := limiter.take(cl.id+"|"+class, limit); !ok {
+		return apierrors.RateLimited("too many "+class+" requests", retryAfter)
+	}
+
+	return nil
+}
+
+// Clients with the admin scope are not rate limited
+func (cl *client) unlimited() bool {
+	return slices.Contains(cl.scopes, ScopeAdmin)
+}
+
+// Consume a daily quota of the client. Quotas are reset at midnight UTC.
+// The quota is consumed before running the action, the returned function gives back part of it if the action fails
+func consumeQuota(c *gin.Context, quota string, amount int64) (func(int64), error) {
+	noop := func(int64) {}
+
+	cl, err := authenticate(c)
+	if err != nil {
+		return noop, err
+	}
+
+	if cl.unlimited() {
+		return noop, nil
+	}
+
+	limit := dailyQuota(quota)
+	now := time.Now().Unix()
+	day := now / 86400
+
+	used, ok, err := database.ConsumeQuota(cl.id, day, quota, amount, limit)
+	if err != nil {
+		return noop, apierrors.Wrap("Error while checking quota", err)
+	}
+
+	if !ok {
+		retryAfter := int((day+1)*86400 - now)
+		return noop, apierrors.RateLimited("daily quota "+quota+" exceeded, used "+strconv.FormatInt(used, 10)+" of "+strconv.FormatInt(limit, 10), retryAfter)
+	}
+
+	refund := func(unused int64) {
+		if err := database.RefundQuota(cl.id, day, quota, unused); err != nil {
+			log.Println("error while refunding quota:", err)
+		}
+	}
+
+	return refund, nil
+}
+
+func dailyQuota(quota string) int64 {
+	switch quota {
+	case quotaUploadBytes:
+		if apiConfig.DailyUploadBytes > 0 {
+			return apiConfig.DailyUploadBytes
+		}
+		return defaultDailyUploadBytes
+	case quotaOrders:
+		if apiConfig.DailyOrders > 0 {
+			return apiConfig.DailyOrders
+		}
+		return defaultDailyOrders
+	default:
+		return 0
+	}
+}
@@ -0,0 +1,237 @@
+package api
+
+import (
+	"errors"
+	"go-printful-api/src/apierrors"
+	"reflect"
+	"slices"
+	"strconv"
+	"strings"
+
+	printfulsdk "github.com/baldurstod/go-printful-sdk"
+	"github.com/gin-gonic/gin"
+	"github.com/go-playground/validator/v10"
+	"github.com/mitchellh/mapstructure"
+)
+
+type action interface {
+	handle(c *gin.Context, params map[string]interface{}) (any, error)
+	requestType() reflect.Type
+}
+
+type versions map[int]action
+
+// Supported versions, in ascending order
+func (v versions) String() string {
+	numbers := make([]int, 0, len(v))
+	for n := range v {
+		numbers = append(numbers, n)
+	}
+	slices.Sort(numbers)
+
+	strs := make([]string, 0, len(numbers))
+	for _, n := range numbers {
+		strs = append(strs, strconv.Itoa(n))
+	}
+	return strings.Join(strs, ", ")
+}
+
+// Action decoding its params into a request of type T before calling the handler
+type typedAction[T any] struct {
+	handler func(c *gin.Context, request *T) (any, error)
+}
+
+func (a typedAction[T]) handle(c *gin.Context, params map[string]interface{}) (any, error) {
+	request := new(T)
+	if err := decodeRequest(params, request); err != nil {
+		return nil, err
+	}
+
+	return a.handler(c, request)
+}
+
+func (a typedAction[T]) requestType() reflect.Type {
+	return reflect.TypeFor[T]()
+}
+
+var requestValidator = newRequestValidator()
+
+func newRequestValidator() *validator.Validate {
+	v := validator.New(validator.WithRequiredStructEnabled())
+
+	// Report errors using the param names
+	v.RegisterTagNameFunc(func(field reflect.StructField) string {
+		name, _, _ := strings.Cut(field.Tag.Get("mapstructure"), ",")
+		if name == "" {
+			return field.Name
+		}
+		return name
+	})
+
+	return v
+}
+
+// Decode params into a request, apply default values then validate the request
+func decodeRequest(params map[string]interface{}, request any) error {
+	if err := applyDefaults(reflect.ValueOf(request).Elem()); err != nil {
+		return err
+	}
+
+	decoder, err := mapstructure.NewDecoder(&mapstructure.DecoderConfig{
+		Result: request,
+	})
+	if err != nil {
+		return err
+	}
+
+	if err = decoder.Decode(params); err != nil {
+		return invalidParams(decodeErrors(err))
+	}
+
+	applyFallbacks(reflect.ValueOf(request).Elem())
+
+	if err = requestValidator.Struct(request); err != nil {
+		return invalidParams(validationErrors(err))
+	}
+
+	return nil
+}
+
+func invalidParams(err error) error {
+	var requestError RequestError
+	if errors.As(err, &requestError) {
+		return apierrors.Validation(requestError.Error(), requestError)
+	}
+	return err
+}
+
+// Set fields tagged with a default value. Only the top level fields and squashed structs are set
+func applyDefaults(v reflect.Value) error {
+	if v.Kind() != reflect.Struct {
+		return nil
+	}
+
+	t := v.Type()
+	for i := range t.NumField() {
+		field := t.Field(i)
+		value := v.Field(i)
+
+		if field.Anonymous {
+			if err := applyDefaults(value); err != nil {
+				return err
+			}
+			continue
+		}
+
+		def, ok := field.Tag.Lookup("default")
+		if !ok {
+			continue
+		}
+
+		switch value.Kind() {
+		case reflect.String:
+			value.SetString(def)
+		case reflect.Int, reflect.Int64:
+			i, err := strconv.ParseInt(def, 10, 64)
+			if err != nil {
+				return errors.New("invalid default value for field " + field.Name)
+			}
+			value.SetInt(i)
+		default:
+			return errors.New("unsupported default value for field " + field.Name)
+		}
+	}
+
+	return nil
+}
+
+// Reset fields tagged with a fallback to their default value when the value is not supported.
+// Unsupported languages fall back to en_US instead of failing the request
+func applyFallbacks(v reflect.Value) {
+	if v.Kind() != reflect.Struct {
+		return
+	}
+
+	t := v.Type()
+	for i := range t.NumField() {
+		field := t.Field(i)
+		value := v.Field(i)
+
+		if field.Anonymous {
+			applyFallbacks(value)
+			continue
+		}
+
+		switch field.Tag.Get("fallback") {
+		case "language":
+			if !slices.Contains(printfulsdk.Languages, value.String()) {
+				value.SetString(field.Tag.Get("default"))
+			}
+		}
+	}
+}
+
+// Convert mapstructure errors like "'product_id' expected type 'int', got unconvertible type 'string'" to field errors
+func decodeErrors(err error) error {
+	var mapstructureError *mapstructure.Error
+	if !errors.As(err, &mapstructureError) {
+		return RequestError{Fields: []FieldError{{Field: "params", Message: err.Error()}}}
+	}
+
+	fields := make([]FieldError, 0, len(mapstructureError.Errors))
+	for _, e := range mapstructureError.Errors {
+		field := "params"
+		if start := strings.IndexByte(e, '\''); start != -1 {
+			if end := strings.IndexByte(e[start+1:], '\''); end != -1 {
+				field = e[start+1 : start+1+end]
+			}
+		}
+
+		message := "has an invalid type"
+		if _, expected, found := strings.Cut(e, "expected type "); found {
+			expected, _, _ = strings.Cut(expected, ",")
+			message = "must be of type " + strings.Trim(expected, "'")
+		}
+
+		fields = append(fields, FieldError{Field: field, Message: message})
+	}
+
+	return RequestError{Fields: fields}
+}
+
+func validationErrors(err error) error {
+	var validationErrors validator.ValidationErrors
+	if !errors.As(err, &validationErrors) {
+		return err
+	}
+
+	fields := make([]FieldError, 0, len(validationErrors))
+	for _, e := range validationErrors {
+		// Remove the struct name from the namespace
+		_, field, _ := strings.Cut(e.Namespace(), ".")
+		fields = append(fields, FieldError{Field: field, Message: validationMessage(e)})
+	}
+
+	return RequestError{Fields: fields}
+}
+
+func validationMessage(e validator.FieldError) string {
+	switch e.Tag() {
+	case "required":
+		return "is required"
+	case "gt":
+		return "must be greater than " + e.Param()
+	case "gte":
+		return "must be greater than or equal to " + e.Param()
+	case "min":
+		return "must contain at least " + e.Param() + " element(s)"
+	case "oneof":
+		return "must be one of: " + strings.ReplaceAll(e.Param(), " ", ", ")
+	case "iso4217":
+		return "must be a valid currency code"
+	case "iso3166_1_alpha2":
+		return "must be a valid country code"
+	default:
+		return "is invalid"
+	}
+}
@@ -0,0 +1,119 @@
+package api
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"go-printful-api/src/apierrors"
+	"net/http"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+)
+
+const defaultRestMaxAge = 300
+
+// Query params decoded as numbers or as comma separated lists
+var numericQueryParams = []string{"limit", "offset", "days", "since", "variant_id", "mockup_style_id"}
+var listQueryParams = []string{"fields", "categories"}
+
+// Returns a read-only GET handler running the version 1 of an action. The :id path param is passed as idParam
+func RestHandler(actionName string, idParam string) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		params, err := restParams(c, idParam)
+		if err != nil {
+			restError(c, err)
+			return
+		}
+
+		result, err := runAction(c, &ApiRequest{Action: actionName, Version: 1, Params: params})
+		if err != nil {
+			restError(c, err)
+			return
+		}
+
+		body, err := json.Marshal(successEnvelope(result))
+		if err != nil {
+			restError(c, err)
+			return
+		}
+
+		hash := sha256.Sum256(body)
+		etag := `"` + hex.EncodeToString(hash[:16]) + `"`
+
+		maxAge := apiConfig.RestMaxAge
+		if maxAge <= 0 {
+			maxAge = defaultRestMaxAge
+		}
+
+		// Prices depend on the store, which can be selected by the API key. Responses to API keys are not shared
+		cacheControl := "public"
+		if requestApiKey(c) != "" {
+			cacheControl = "private"
+		}
+
+		c.Header("ETag", etag)
+		c.Header("Cache-Control", cacheControl+", max-age="+strconv.Itoa(maxAge))
+		c.Writer.Header().Add("Vary", "X-Store-Id, Authorization, X-Api-Key")
+
+		if match := c.GetHeader("If-None-Match"); match != "" && (match == etag || match == "*") {
+			c.Status(http.StatusNotModified)
+			return
+		}
+
+		c.Data(http.StatusOK, "application/json; charset=utf-8", body)
+	}
+}
+
+func restParams(c *gin.Context, idParam string) (map[string]interface{}, error) {
+	params := make(map[string]interface{})
+
+	for key, values := range c.Request.URL.Query() {
+		value := values[0]
+		switch {
+		case slices.Contains(numericQueryParams, key):
+			n, err := strconv.ParseFloat(value, 64)
+			if err != nil {
+				return nil, invalidParams(RequestError{Fields: []FieldError{{Field: key, Message: "must be a number"}}})
+			}
+			params[key] = n
+		case slices.Contains(listQueryParams, key):
+			list := make([]interface{}, 0)
+			for _, item := range strings.Split(value, ",") {
+				// Category ids are numbers, invalid ones are reported by the request validation
+				if n, err := strconv.ParseFloat(item, 64); err == nil && key == "categories" {
+					list = append(list, n)
+					continue
+				}
+				list = append(list, item)
+			}
+			params[key] = list
+		default:
+			params[key] = value
+		}
+	}
+
+	if idParam != "" {
+		id, err := strconv.Atoi(c.Param("id"))
+		if err != nil {
+			return nil, invalidParams(RequestError{Fields: []FieldError{{Field: idParam, Message: "must be of type int"}}})
+		}
+		params[idParam] = float64(id)
+	}
+
+	return params, nil
+}
+
+// REST errors always use their HTTP status and are never cached
+func restError(c *gin.Context, e error) {
+	body, apiError := errorBody(e)
+
+	if apiError.Kind == apierrors.KindRateLimited && apiError.RetryAfter > 0 {
+		c.Header("Retry-After", strconv.Itoa(apiError.RetryAfter))
+	}
+	c.Header("Cache-Control", "no-store")
+
+	c.JSON(apiError.HTTPStatus(), errorEnvelope(body))
+}
@@ -0,0 +1,81 @@
+package api
+
+import (
+	"go-printful-api/src/apierrors"
+	"go-printful-api/src/printful"
+
+	"github.com/gin-gonic/gin"
+)
+
+const storeContextKey = "store_id"
+
+// Actions reading or writing the orders and sync products of a store
+var storeActions = map[string]bool{
+	"create-sync-product":      true,
+	"get-sync-product":         true,
+	"calculate-shipping-rates": true,
+	"calculate-tax-rate":       true,
+	"create-order":             true,
+}
+
+// Select the store of a request. Keys bound to a store can only use their store.
+// Store actions are denied to clients not bound to a store, except admin clients
+func selectStore(c *gin.Context, action string, requested string) (string, error) {
+	cl, err := authenticate(c)
+	if err != nil {
+		return "", err
+	}
+
+	if requested == "" {
+		requested = c.GetHeader("X-Store-Id")
+	}
+
+	if cl.key != nil && cl.key.StoreID != "" {
+		if requested != "" && requested != cl.key.StoreID {
+			return "", apierrors.Forbidden("API key is bound to store " + cl.key.StoreID)
+		}
+		return cl.key.StoreID, nil
+	}
+
+	if storeActions[action] && !cl.unlimited() {
+		return "", apierrors.Forbidden("an API key bound to a store is required for action " + action)
+	}
+
+	if requested == "" {
+		return printful.DefaultStoreID, nil
+	}
+
+	if !printful.HasStore(requested) {
+		return "", apierrors.Validation("unknown store "+requested, nil)
+	}
+
+	return requested, nil
+}
+
+func requestStore(c *gin.Context) string {
+	return c.GetString(storeContextKey)
+}
+
+// Currency of the request, or the default currency of the store
+func requestCurrency(c *gin.Context, currency string) (string, error) {
+	if currency != "" {
+		return currency, nil
+	}
+
+	store, err := printful.GetStoreConfig(requestStore(c))
+	if err != nil {
+		return "", err
+	}
+
+	return store.DefaultCurrency, nil
+}
+
+// Url of the uploaded images, the images url of the store
+func storeImagesURL(c *gin.Context) (string, error) {
+	store, err := printful.GetStoreConfig(requestStore(c))
+	if err != nil {
+		return "", err
+	}
+
+	return store.ImagesURL, nil
+}
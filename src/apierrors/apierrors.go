@@ -0,0 +1,153 @@
+package apierrors
+
+import (
+	"database/sql"
+	"errors"
+	"net/http"
+)
+
+type Kind int
+
+const (
+	KindInternal Kind = iota
+	KindValidation
+	KindNotFound
+	KindUpstream
+	KindRateLimited
+	KindUnauthorized
+	KindForbidden
+)
+
+// Stable error codes returned to API clients. Never renumber them
+const (
+	CodeInternal     = 1000
+	CodeValidation   = 1001
+	CodeNotFound     = 1002
+	CodeUpstream     = 1003
+	CodeRateLimited  = 1004
+	CodeUnauthorized = 1005
+	CodeForbidden    = 1006
+)
+
+type Error struct {
+	Kind    Kind
+	Message string
+	// Status and message returned by Printful, for upstream errors
+	UpstreamStatus  int
+	UpstreamMessage string
+	// Seconds to wait before retrying, for rate limited errors
+	RetryAfter int
+	Err        error
+}
+
+func (e *Error) Error() string {
+	if e.Err == nil {
+		return e.Message
+	}
+	return e.Message + ": " + e.Err.Error()
+}
+
+func (e *Error) Unwrap() error {
+	return e.Err
+}
+
+func (e *Error) Code() int {
+	switch e.Kind {
+	case KindValidation:
+		return CodeValidation
+	case KindNotFound:
+		return CodeNotFound
+	case KindUpstream:
+		return CodeUpstream
+	case KindRateLimited:
+		return CodeRateLimited
+	case KindUnauthorized:
+		return CodeUnauthorized
+	case KindForbidden:
+		return CodeForbidden
+	default:
+		return CodeInternal
+	}
+}
+
+func (e *Error) HTTPStatus() int {
+	switch e.Kind {
+	case KindValidation:
+		return http.StatusBadRequest
+	case KindNotFound:
+		return http.StatusNotFound
+	case KindUpstream:
+		// Printful not found errors are reported as is
+		if e.UpstreamStatus == http.StatusNotFound {
+			return http.StatusNotFound
+		}
+		return http.StatusBadGateway
+	case KindRateLimited:
+		return http.StatusTooManyRequests
+	case KindUnauthorized:
+		return http.StatusUnauthorized
+	case KindForbidden:
+		return http.StatusForbidden
+	default:
+		return http.StatusInternalServerError
+	}
+}
+
+func Internal(message string, err error) *Error {
+	return &Error{Kind: KindInternal, Message: message, Err: err}
+}
+
+func Validation(message string, err error) *Error {
+	return &Error{Kind: KindValidation, Message: message, Err: err}
+}
+
+func NotFound(message string, err error) *Error {
+	return &Error{Kind: KindNotFound, Message: message, Err: err}
+}
+
+func Upstream(message string, status int, upstreamMessage string, err error) *Error {
+	return &Error{Kind: KindUpstream, Message: message, UpstreamStatus: status, UpstreamMessage: upstreamMessage, Err: err}
+}
+
+func RateLimited(message string, retryAfter int) *Error {
+	return &Error{Kind: KindRateLimited, Message: message, RetryAfter: retryAfter}
+}
+
+func Unauthorized(message string) *Error {
+	return &Error{Kind: KindUnauthorized, Message: message}
+}
+
+func Forbidden(message string) *Error {
+	return &Error{Kind: KindForbidden, Message: message}
+}
+
+// Replace the message of an error, keeping the kind of the underlying error
+func Wrap(message string, err error) *Error {
+	var apiError *Error
+	if errors.As(err, &apiError) {
+		wrapped := *apiError
+		wrapped.Message = message
+		wrapped.Err = err
+		return &wrapped
+	}
+
+	if errors.Is(err, sql.ErrNoRows) {
+		return NotFound(message, err)
+	}
+
+	return Internal(message, err)
+}
+
+// Returns the typed error of err. Errors without kind are internal errors
+func Classify(err error) *Error {
+	var apiError *Error
+	if errors.As(err, &apiError) {
+		return apiError
+	}
+
+	if errors.Is(err, sql.ErrNoRows) {
+		return NotFound(err.Error(), err)
+	}
+
+	return Internal(err.Error(), err)
+}
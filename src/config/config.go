@@ -7,7 +7,7 @@ type Config struct {
 		Images   Database `json:"images"`
 	} `json:"databases"`
 	Printful Printful `json:"printful"`
-	Api      Printful `json:"api"`
+	Api      Api      `json:"api"`
 }
 
 type HTTP struct {
@@ -22,16 +22,71 @@ type Database struct {
 	BucketName string `json:"bucket_name"`
 }
 
+// Currencies are the currencies of the refreshed prices, USD if empty. The first one is the currency of the default store
 type Printful struct {
+	AccessToken          string           `json:"access_token"`
+	SimulateMockup       bool             `json:"simulate_mockup"`
+	SimulateTaskKey      string           `json:"simulate_task_key"`
+	TaskInterval         int              `json:"task_interval"`
+	MockupDirectory      string           `json:"mockup_directory"`
+	ImagesURL            string           `json:"images_url"`
+	Markup               float64          `json:"markup"`
+	PriceChangeThreshold float64          `json:"price_change_threshold"`
+	RefreshWorkers       int              `json:"refresh_workers"`
+	Currencies           []string         `json:"currencies"`
+	ImageSelections      []ImageSelection `json:"image_selections"`
+	MirrorImages         bool             `json:"mirror_images"`
+	// Larger images are not mirrored, in bytes. Defaults to 20 MiB
+	MirrorMaxImageSize int64 `json:"mirror_max_image_size"`
+	// Additional Printful stores. The store above is the default store and the only one used to refresh the catalog
+	Stores []Store `json:"stores"`
+}
+
+// Printful store used for orders and sync products. Stores share the catalog.
+// The images url and currency default to the ones of the default store
+type Store struct {
+	ID              string  `json:"id"`
 	AccessToken     string  `json:"access_token"`
-	SimulateMockup  bool    `json:"simulate_mockup"`
-	SimulateTaskKey string  `json:"simulate_task_key"`
-	TaskInterval    int     `json:"task_interval"`
-	MockupDirectory string  `json:"mockup_directory"`
-	ImagesURL       string  `json:"images_url"`
 	Markup          float64 `json:"markup"`
+	ImagesURL       string  `json:"images_url"`
+	DefaultCurrency string  `json:"default_currency"`
+}
+
+// Rule used to pick a tagged image for each product among Printful mockups
+type ImageSelection struct {
+	Tag                      string   `json:"tag"`
+	CategoryPrefix           string   `json:"category_prefix"`
+	ExcludedCategoryPrefixes []string `json:"excluded_category_prefixes"`
+	ViewName                 string   `json:"view_name"`
+	PreferredColors          []string `json:"preferred_colors"`
 }
 
 type Api struct {
+	// Deprecated: uploaded images use the images url of the store, see Printful.ImagesURL and Store.ImagesURL
 	ImagesURL string `json:"images_url"`
+	// Send errors with their HTTP status code instead of 200
+	HTTPStatusCodes bool `json:"http_status_codes"`
+	// Maximum number of requests in a batch and number of requests of a batch executed at the same time
+	BatchMaxSize     int `json:"batch_max_size"`
+	BatchConcurrency int `json:"batch_concurrency"`
+	// Cache-Control max-age of the GET routes, in seconds
+	RestMaxAge int `json:"rest_max_age"`
+	// Key with every scope, used to create the first API keys
+	AdminKey string `json:"admin_key"`
+	// Scopes granted to requests without an API key. Defaults to catalog:read
+	AnonymousScopes []string `json:"anonymous_scopes"`
+	// Origins allowed for every client, in addition to the allowed origins of each API key.
+	// Browser requests from other origins are rejected, the origins of the frontends must be listed here
+	AllowedOrigins []string `json:"allowed_origins"`
+	// Request rate of each client, by action class. Action classes are the scopes required by the actions
+	RateLimits map[string]RateLimit `json:"rate_limits"`
+	// Daily quotas of each client
+	DailyUploadBytes int64 `json:"daily_upload_bytes"`
+	DailyOrders      int64 `json:"daily_orders"`
+}
+
+// Token bucket refilled at Rate tokens per second, holding at most Burst tokens
+type RateLimit struct {
+	Rate  float64 `json:"rate"`
+	Burst int     `json:"burst"`
 }
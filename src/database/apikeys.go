@@ -0,0 +1,186 @@
+package database
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"slices"
+	"strconv"
+	"time"
+)
+
+// API key of a client. Only the sha256 hash of the key is stored
+type ApiKey struct {
+	ID      int    `json:"id"`
+	Name    string `json:"name"`
+	KeyHash string `json:"-"`
+	// Store the key is bound to. Keys not bound to a store can select any store
+	StoreID        string   `json:"store_id"`
+	Scopes         []string `json:"scopes"`
+	AllowedOrigins []string `json:"allowed_origins"`
+	Revoked        bool     `json:"revoked"`
+	DateCreated    int64    `json:"date_created"`
+	DateRevoked    int64    `json:"date_revoked"`
+}
+
+func (k ApiKey) clone() ApiKey {
+	k.Scopes = slices.Clone(k.Scopes)
+	k.AllowedOrigins = slices.Clone(k.AllowedOrigins)
+	return k
+}
+
+var apiKeysCache = newMemoryCache[string, ApiKey]("api_keys")
+var apiKeyListCache = newMemoryCache[int, []ApiKey]("api_key_list")
+
+func InsertApiKey(key *ApiKey) (int, error) {
+	if printfulDb == nil {
+		return 0, errors.New("database is not initialized. Did you forgot to init postgre ?")
+	}
+
+	scopes, err := json.Marshal(key.Scopes)
+	if err != nil {
+		return 0, fmt.Errorf("failed to marshal scopes: <%w>", err)
+	}
+
+	allowedOrigins, err := json.Marshal(key.AllowedOrigins)
+	if err != nil {
+		return 0, fmt.Errorf("failed to marshal allowed origins: <%w>", err)
+	}
+
+	var id int
+	err = printfulDb.QueryRow(`INSERT INTO api_keys (name, key_hash, store_id, scopes, allowed_origins, revoked, date_created, date_revoked)
+	VALUES ($1, $2, $3, $4, $5, $6, $7, $8)
+	RETURNING id`,
+		key.Name,
+		key.KeyHash,
+		key.StoreID,
+		scopes,
+		allowedOrigins,
+		key.Revoked,
+		key.DateCreated,
+		key.DateRevoked,
+	).Scan(&id)
+
+	if err != nil {
+		return 0, fmt.Errorf("failed to insert api key "+key.Name+" : <%w>", err)
+	}
+
+	apiKeyListCache.clear()
+
+	return id, nil
+}
+
+// Revoke a key. Returns sql.ErrNoRows if the key doesn't exist
+func RevokeApiKey(id int) error {
+	if printfulDb == nil {
+		return errors.New("database is not initialized. Did you forgot to init postgre ?")
+	}
+
+	var keyHash string
+	err := printfulDb.QueryRow(`UPDATE api_keys SET revoked = TRUE, date_revoked = $2 WHERE id = $1 RETURNING key_hash`,
+		id,
+		time.Now().Unix(),
+	).Scan(&keyHash)
+
+	if err != nil {
+		return fmt.Errorf("failed to revoke api key "+strconv.Itoa(id)+" : <%w>", err)
+	}
+
+	apiKeysCache.invalidate(keyHash)
+	apiKeyListCache.clear()
+
+	return nil
+}
+
+// Find a key by the hash of its value. Revoked keys are returned too
+func FindApiKeyByHash(keyHash string) (ApiKey, error) {
+	key, _, err := cached(apiKeysCache, keyHash, func() (ApiKey, int64, error) {
+		key, err := findApiKey(`WHERE key_hash = $1`, keyHash)
+		return key, 0, err
+	})
+	if err != nil {
+		return ApiKey{}, err
+	}
+
+	return key.clone(), nil
+}
+
+func FindApiKeys() ([]ApiKey, error) {
+	keys, _, err := cached(apiKeyListCache, 0, func() ([]ApiKey, int64, error) {
+		keys, err := findApiKeys()
+		return keys, 0, err
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	clones := make([]ApiKey, 0, len(keys))
+	for _, key := range keys {
+		clones = append(clones, key.clone())
+	}
+	return clones, nil
+}
+
+func findApiKeys() ([]ApiKey, error) {
+	if printfulDb == nil {
+		return nil, errors.New("database is not initialized. Did you forgot to init postgre ?")
+	}
+
+	query := `SELECT ` + apiKeyColumns + ` FROM api_keys ORDER BY id;`
+	res, err := printfulDb.Query(query)
+	if err != nil {
+		return nil, fmt.Errorf("failed to execute query "+query+"in FindApiKeys: <%w>", err)
+	}
+	defer res.Close()
+
+	keys := make([]ApiKey, 0)
+	for res.Next() {
+		key, err := scanApiKey(res)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan row in FindApiKeys: <%w>", err)
+		}
+		keys = append(keys, key)
+	}
+
+	if err := res.Err(); err != nil {
+		return nil, fmt.Errorf("failed to get next row in FindApiKeys: <%w>", err)
+	}
+
+	return keys, nil
+}
+
+const apiKeyColumns = `id, name, key_hash, store_id, scopes, allowed_origins, revoked, date_created, date_revoked`
+
+func findApiKey(where string, args ...any) (ApiKey, error) {
+	if printfulDb == nil {
+		return ApiKey{}, errors.New("database is not initialized. Did you forgot to init postgre ?")
+	}
+
+	row := printfulDb.QueryRow(`SELECT `+apiKeyColumns+` FROM api_keys `+where+`;`, args...)
+
+	key, err := scanApiKey(row)
+	if err != nil {
+		return ApiKey{}, fmt.Errorf("failed to scan row in findApiKey: <%w>", err)
+	}
+
+	return key, nil
+}
+
+func scanApiKey(row interface{ Scan(...any) error }) (ApiKey, error) {
+	var key ApiKey
+	var scopes, allowedOrigins string
+
+	err := row.Scan(&key.ID, &key.Name, &key.KeyHash, &key.StoreID, &scopes, &allowedOrigins, &key.Revoked, &key.DateCreated, &key.DateRevoked)
+	if err != nil {
+		return ApiKey{}, err
+	}
+
+	if err = json.Unmarshal([]byte(scopes), &key.Scopes); err != nil {
+		return ApiKey{}, err
+	}
+	if err = json.Unmarshal([]byte(allowedOrigins), &key.AllowedOrigins); err != nil {
+		return ApiKey{}, err
+	}
+
+	return key, nil
+}
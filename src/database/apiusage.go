@@ -0,0 +1,81 @@
+package database
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+)
+
+// Add amount to the usage of a quota by a client during a day. The usage is not changed if it would exceed limit.
+// Returns the usage of the quota and whether amount was added
+func ConsumeQuota(client string, day int64, quota string, amount int64, limit int64) (int64, bool, error) {
+	if printfulDb == nil {
+		return 0, false, errors.New("database is not initialized. Did you forgot to init postgre ?")
+	}
+
+	if amount > limit {
+		used, err := findQuotaUsage(client, day, quota)
+		return used, false, err
+	}
+
+	var used int64
+	err := printfulDb.QueryRow(`INSERT INTO api_usage (client, day, quota, used)
+	VALUES ($1, $2, $3, $4)
+	ON CONFLICT (client, day, quota) DO UPDATE SET
+	used = api_usage.used + $4
+	WHERE api_usage.used + $4 <= $5
+	RETURNING used`,
+		client,
+		day,
+		quota,
+		amount,
+		limit,
+	).Scan(&used)
+
+	if errors.Is(err, sql.ErrNoRows) {
+		used, err = findQuotaUsage(client, day, quota)
+		return used, false, err
+	}
+
+	if err != nil {
+		return 0, false, fmt.Errorf("failed to consume quota "+quota+" of "+client+" : <%w>", err)
+	}
+
+	return used, true, nil
+}
+
+// Remove amount from the usage of a quota, used when the action consuming the quota failed
+func RefundQuota(client string, day int64, quota string, amount int64) error {
+	if printfulDb == nil {
+		return errors.New("database is not initialized. Did you forgot to init postgre ?")
+	}
+
+	_, err := printfulDb.Exec(`UPDATE api_usage SET used = GREATEST(used - $4, 0) WHERE client = $1 AND day = $2 AND quota = $3`,
+		client,
+		day,
+		quota,
+		amount,
+	)
+
+	if err != nil {
+		return fmt.Errorf("failed to refund quota "+quota+" of "+client+" : <%w>", err)
+	}
+
+	return nil
+}
+
+func findQuotaUsage(client string, day int64, quota string) (int64, error) {
+	query := `SELECT used FROM api_usage WHERE client = $1 AND day = $2 AND quota = $3;`
+	row := printfulDb.QueryRow(query, client, day, quota)
+
+	var used int64
+	err := row.Scan(&used)
+	if errors.Is(err, sql.ErrNoRows) {
+		return 0, nil
+	}
+	if err != nil {
+		return 0, fmt.Errorf("failed to scan row in findQuotaUsage: <%w>", err)
+	}
+
+	return used, nil
+}
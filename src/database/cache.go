@@ -0,0 +1,134 @@
+package database
+
+import (
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+type CacheStats struct {
+	Name     string  `json:"name"`
+	Entries  int     `json:"entries"`
+	Hits     int64   `json:"hits"`
+	Misses   int64   `json:"misses"`
+	HitRatio float64 `json:"hit_ratio"`
+}
+
+type cacheEntry[V any] struct {
+	value V
+	// Unix time of the last update of the value in the database, 0 if the value can't be outdated
+	lastUpdated int64
+	expires     int64
+}
+
+// In-memory cache in front of the database. Entries expire after cacheMaxAge and are invalidated on writes
+type memoryCache[K comparable, V any] struct {
+	name    string
+	mutex   sync.RWMutex
+	entries map[K]cacheEntry[V]
+	hits    atomic.Int64
+	misses  atomic.Int64
+}
+
+var caches = make([]interface {
+	stats() CacheStats
+	clear()
+}, 0)
+
+func newMemoryCache[K comparable, V any](name string) *memoryCache[K, V] {
+	c := &memoryCache[K, V]{
+		name:    name,
+		entries: make(map[K]cacheEntry[V]),
+	}
+	caches = append(caches, c)
+	return c
+}
+
+func (c *memoryCache[K, V]) get(key K) (V, bool, bool) {
+	c.mutex.RLock()
+	entry, found := c.entries[key]
+	c.mutex.RUnlock()
+
+	if !found || time.Now().Unix() > entry.expires {
+		c.misses.Add(1)
+		var zero V
+		return zero, false, false
+	}
+
+	c.hits.Add(1)
+	return entry.value, isOutdated(entry.lastUpdated), true
+}
+
+func (c *memoryCache[K, V]) set(key K, value V, lastUpdated int64) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	c.entries[key] = cacheEntry[V]{value: value, lastUpdated: lastUpdated, expires: time.Now().Unix() + cacheMaxAge}
+}
+
+func (c *memoryCache[K, V]) invalidate(key K) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	delete(c.entries, key)
+}
+
+func (c *memoryCache[K, V]) clear() {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	c.entries = make(map[K]cacheEntry[V])
+}
+
+func (c *memoryCache[K, V]) stats() CacheStats {
+	c.mutex.RLock()
+	entries := len(c.entries)
+	c.mutex.RUnlock()
+
+	stats := CacheStats{
+		Name:    c.name,
+		Entries: entries,
+		Hits:    c.hits.Load(),
+		Misses:  c.misses.Load(),
+	}
+
+	if total := stats.Hits + stats.Misses; total > 0 {
+		stats.HitRatio = float64(stats.Hits) / float64(total)
+	}
+
+	return stats
+}
+
+// Values are outdated cacheMaxAge after their last update. Freshness is computed on each read, not when the value is cached
+func isOutdated(lastUpdated int64) bool {
+	return lastUpdated != 0 && time.Now().Unix()-lastUpdated > cacheMaxAge
+}
+
+// Read a value through a cache. find returns the value and the Unix time of its last update, or 0 if the value can't be outdated.
+// Only successful reads are cached
+func cached[K comparable, V any](c *memoryCache[K, V], key K, find func() (V, int64, error)) (V, bool, error) {
+	if value, outdated, found := c.get(key); found {
+		return value, outdated, nil
+	}
+
+	value, lastUpdated, err := find()
+	if err == nil {
+		c.set(key, value, lastUpdated)
+	}
+
+	return value, isOutdated(lastUpdated), err
+}
+
+func GetCacheStats() []CacheStats {
+	stats := make([]CacheStats, 0, len(caches))
+	for _, c := range caches {
+		stats = append(stats, c.stats())
+	}
+	return stats
+}
+
+func clearCaches() {
+	for _, c := range caches {
+		c.clear()
+	}
+}
@@ -0,0 +1,92 @@
+package database
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strconv"
+	"time"
+)
+
+type CatalogChangeType string
+
+const (
+	ProductAdded        CatalogChangeType = "product_added"
+	ProductRemoved      CatalogChangeType = "product_removed"
+	ProductDiscontinued CatalogChangeType = "product_discontinued"
+	VariantAdded        CatalogChangeType = "variant_added"
+	VariantRemoved      CatalogChangeType = "variant_removed"
+	PlacementsChanged   CatalogChangeType = "placements_changed"
+	TechniquesChanged   CatalogChangeType = "techniques_changed"
+)
+
+type CatalogChange struct {
+	ID          int               `json:"id"`
+	ProductID   int               `json:"product_id"`
+	VariantID   int               `json:"variant_id,omitempty"`
+	ChangeType  CatalogChangeType `json:"change_type"`
+	Details     map[string]any    `json:"details,omitempty"`
+	DateCreated int64             `json:"date_created"`
+}
+
+func InsertCatalogChange(change *CatalogChange) error {
+	if printfulDb == nil {
+		return errors.New("database is not initialized. Did you forgot to init postgre ?")
+	}
+
+	details, err := json.Marshal(&change.Details)
+	if err != nil {
+		return fmt.Errorf("failed to marshal change.Details: <%w>", err)
+	}
+
+	_, err = printfulDb.Exec(`INSERT INTO catalog_changes (product_id, variant_id, change_type, details, date_created)
+	VALUES ($1, $2, $3, $4, $5)`,
+		change.ProductID,
+		change.VariantID,
+		change.ChangeType,
+		details,
+		time.Now().Unix(),
+	)
+
+	if err != nil {
+		return fmt.Errorf("failed to insert catalog change "+strconv.Itoa(change.ProductID)+" "+string(change.ChangeType)+" : <%w>", err)
+	}
+
+	return nil
+}
+
+func FindCatalogChanges(since int64) ([]CatalogChange, error) {
+	if printfulDb == nil {
+		return nil, errors.New("database is not initialized. Did you forgot to init postgre ?")
+	}
+
+	query := `SELECT id, product_id, variant_id, change_type, details, date_created FROM catalog_changes WHERE date_created >= $1 ORDER BY id;`
+	res, err := printfulDb.Query(query, since)
+	if err != nil {
+		return nil, fmt.Errorf("failed to execute query "+query+"in FindCatalogChanges: <%w>", err)
+	}
+	defer res.Close()
+
+	changes := make([]CatalogChange, 0, 20)
+	for res.Next() {
+		var change CatalogChange
+		var details string
+
+		err = res.Scan(&change.ID, &change.ProductID, &change.VariantID, &change.ChangeType, &details, &change.DateCreated)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan row in FindCatalogChanges: <%w>", err)
+		}
+
+		if err = json.Unmarshal([]byte(details), &change.Details); err != nil {
+			return nil, err
+		}
+
+		changes = append(changes, change)
+	}
+
+	if err := res.Err(); err != nil {
+		return nil, fmt.Errorf("failed to get next row in FindCatalogChanges: <%w>", err)
+	}
+
+	return changes, nil
+}
@@ -3,12 +3,15 @@ package database
 import (
 	"errors"
 	"fmt"
+	"slices"
 	"strconv"
 	"time"
 
 	printfulmodel "github.com/baldurstod/go-printful-sdk/model"
 )
 
+var categoriesCache = newMemoryCache[string, []printfulmodel.Category]("categories")
+
 func InsertCategory(category *printfulmodel.Category, language string) error {
 	if printfulDb == nil {
 		return errors.New("database is not initialized. Did you forgot to init postgre ?")
@@ -33,16 +36,30 @@ func InsertCategory(category *printfulmodel.Category, language string) error {
 		return fmt.Errorf("failed to insert category "+strconv.Itoa(category.ID)+" : <%w>", err)
 	}
 
+	categoriesCache.invalidate(language)
+
 	return nil
 }
 
-func FindCategories() ([]printfulmodel.Category, error) {
+func FindCategories(language string) ([]printfulmodel.Category, error) {
+	categories, _, err := cached(categoriesCache, language, func() ([]printfulmodel.Category, int64, error) {
+		categories, err := findCategories(language)
+		return categories, 0, err
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return slices.Clone(categories), nil
+}
+
+func findCategories(language string) ([]printfulmodel.Category, error) {
 	if printfulDb == nil {
 		return nil, errors.New("database is not initialized. Did you forgot to init postgre ?")
 	}
 
-	query := `SELECT id, parent_id, image_url, title FROM categories;`
-	res, err := printfulDb.Query(query)
+	query := `SELECT id, parent_id, image_url, title FROM categories WHERE language = $1 ORDER BY id;`
+	res, err := printfulDb.Query(query, language)
 	if err != nil {
 		return nil, fmt.Errorf("failed to execute query "+query+"in FindCategories: <%w>", err)
 	}
@@ -4,11 +4,14 @@ import (
 	"encoding/json"
 	"errors"
 	"fmt"
+	"slices"
 	"time"
 
 	printfulmodel "github.com/baldurstod/go-printful-sdk/model"
 )
 
+var countriesCache = newMemoryCache[int, []printfulmodel.Country]("countries")
+
 func InsertCountry(country *printfulmodel.Country) error {
 	if printfulDb == nil {
 		return errors.New("database is not initialized. Did you forgot to init postgre ?")
@@ -37,10 +40,24 @@ func InsertCountry(country *printfulmodel.Country) error {
 		return fmt.Errorf("failed to insert country "+country.Code+" : <%w>", err)
 	}
 
+	countriesCache.clear()
+
 	return nil
 }
 
 func FindCountries() ([]printfulmodel.Country, error) {
+	countries, _, err := cached(countriesCache, 0, func() ([]printfulmodel.Country, int64, error) {
+		countries, err := findCountries()
+		return countries, 0, err
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return slices.Clone(countries), nil
+}
+
+func findCountries() ([]printfulmodel.Country, error) {
 	if printfulDb == nil {
 		return nil, errors.New("database is not initialized. Did you forgot to init postgre ?")
 	}
@@ -27,7 +27,7 @@ func UploadImage(filename string, img image.Image) error {
 	created = $3`,
 		filename,
 		buf,
-		time.Now().Unix(),
+		time.Now(),
 	)
 
 	if err != nil {
@@ -57,20 +57,39 @@ func UploadImage(filename string, img image.Image) error {
 	*/
 }
 
+// Store an image as is, without re-encoding it
+func UploadImageData(filename string, data []byte) error {
+	if imagesDb == nil {
+		return errors.New("database is not initialized. Did you forgot to init postgre ?")
+	}
+
+	_, err := imagesDb.Exec(`INSERT INTO images (filename, image, created)
+	VALUES ($1, $2, $3)
+	ON CONFLICT (filename) DO UPDATE SET
+	image = $2,
+	created = $3`,
+		filename,
+		data,
+		time.Now(),
+	)
+
+	if err != nil {
+		return fmt.Errorf("failed to insert image "+filename+" : <%w>", err)
+	}
+
+	return nil
+}
+
 func GetImage(filename string) ([]byte, error) {
-	return nil, nil
-	/*
-		downloadStream, err := imagesBucket.OpenDownloadStreamByName(filename)
-		if err != nil {
-			return nil, err
-		}
-		defer downloadStream.Close()
+	if imagesDb == nil {
+		return nil, errors.New("database is not initialized. Did you forgot to init postgre ?")
+	}
 
-		p := make([]byte, downloadStream.GetFile().Length)
-		if _, err = downloadStream.Read(p); err != nil {
-			return nil, err
-		}
+	var image []byte
+	err := imagesDb.QueryRow(`SELECT image FROM images WHERE filename = $1;`, filename).Scan(&image)
+	if err != nil {
+		return nil, fmt.Errorf("failed to scan row in GetImage: <%w>", err)
+	}
 
-		return p, nil
-	*/
+	return image, nil
 }
@@ -0,0 +1,79 @@
+package database
+
+import (
+	"errors"
+	"fmt"
+	"time"
+)
+
+// Local image id of each mirrored upstream url
+var mirroredImagesCache = newMemoryCache[int, map[string]string]("mirrored_images")
+
+func InsertMirroredImage(sourceURL string, imageID string) error {
+	if printfulDb == nil {
+		return errors.New("database is not initialized. Did you forgot to init postgre ?")
+	}
+
+	_, err := printfulDb.Exec(`INSERT INTO mirrored_images (source_url, image_id, last_updated)
+	VALUES ($1, $2, $3)
+	ON CONFLICT (source_url) DO UPDATE SET
+	image_id = $2,
+	last_updated = $3`,
+		sourceURL,
+		imageID,
+		time.Now().Unix(),
+	)
+
+	if err != nil {
+		return fmt.Errorf("failed to insert mirrored image "+sourceURL+" : <%w>", err)
+	}
+
+	mirroredImagesCache.clear()
+
+	return nil
+}
+
+// Returns the local image id of a mirrored upstream url. The cached map is shared and must not be modified
+func FindMirroredImage(sourceURL string) (string, bool, error) {
+	images, _, err := cached(mirroredImagesCache, 0, func() (map[string]string, int64, error) {
+		images, err := findMirroredImages()
+		return images, 0, err
+	})
+	if err != nil {
+		return "", false, err
+	}
+
+	imageID, found := images[sourceURL]
+	return imageID, found, nil
+}
+
+func findMirroredImages() (map[string]string, error) {
+	if printfulDb == nil {
+		return nil, errors.New("database is not initialized. Did you forgot to init postgre ?")
+	}
+
+	query := `SELECT source_url, image_id FROM mirrored_images;`
+	res, err := printfulDb.Query(query)
+	if err != nil {
+		return nil, fmt.Errorf("failed to execute query "+query+"in FindMirroredImages: <%w>", err)
+	}
+	defer res.Close()
+
+	images := make(map[string]string)
+	for res.Next() {
+		var sourceURL, imageID string
+
+		err = res.Scan(&sourceURL, &imageID)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan row in FindMirroredImages: <%w>", err)
+		}
+
+		images[sourceURL] = imageID
+	}
+
+	if err := res.Err(); err != nil {
+		return nil, fmt.Errorf("failed to get next row in FindMirroredImages: <%w>", err)
+	}
+
+	return images, nil
+}
@@ -4,12 +4,15 @@ import (
 	"encoding/json"
 	"errors"
 	"fmt"
+	"slices"
 	"strconv"
 	"time"
 
 	printfulmodel "github.com/baldurstod/go-printful-sdk/model"
 )
 
+var templatesCache = newMemoryCache[int, []printfulmodel.MockupTemplates]("templates")
+
 func InsertMockupTemplates(productID int, mockupTemplates []printfulmodel.MockupTemplates) error {
 	if printfulDb == nil {
 		return errors.New("database is not initialized. Did you forgot to init postgre ?")
@@ -34,12 +37,25 @@ func InsertMockupTemplates(productID int, mockupTemplates []printfulmodel.Mockup
 		return fmt.Errorf("failed to insert mockup templates "+strconv.Itoa(productID)+" : <%w>", err)
 	}
 
+	templatesCache.invalidate(productID)
+
 	return nil
 }
 
 func FindMockupTemplates(productID int) ([]printfulmodel.MockupTemplates, bool, error) {
+	templates, outdated, err := cached(templatesCache, productID, func() ([]printfulmodel.MockupTemplates, int64, error) {
+		return findMockupTemplates(productID)
+	})
+	if err != nil {
+		return nil, false, err
+	}
+
+	return slices.Clone(templates), outdated, nil
+}
+
+func findMockupTemplates(productID int) ([]printfulmodel.MockupTemplates, int64, error) {
 	if printfulDb == nil {
-		return nil, false, errors.New("database is not initialized. Did you forgot to init postgre ?")
+		return nil, 0, errors.New("database is not initialized. Did you forgot to init postgre ?")
 	}
 
 	query := `SELECT mockup_templates, last_updated FROM mockup_templates WHERE product_id = $1;`
@@ -50,13 +66,13 @@ func FindMockupTemplates(productID int) ([]printfulmodel.MockupTemplates, bool,
 
 	err := row.Scan(&mockupTemplates, &lastUpdated)
 	if err != nil {
-		return nil, false, fmt.Errorf("failed to scan row in FindMockupTemplates: <%w>", err)
+		return nil, 0, fmt.Errorf("failed to scan row in FindMockupTemplates: <%w>", err)
 	}
 
 	templates := []printfulmodel.MockupTemplates{}
 	if err = json.Unmarshal([]byte(mockupTemplates), &templates); err != nil {
-		return nil, false, err
+		return nil, 0, err
 	}
 
-	return templates, time.Now().Unix()-lastUpdated > cacheMaxAge, nil
+	return templates, lastUpdated, nil
 }
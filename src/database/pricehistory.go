@@ -0,0 +1,128 @@
+package database
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"time"
+
+	printfulmodel "github.com/baldurstod/go-printful-sdk/model"
+)
+
+type PriceHistory struct {
+	ProductID       int    `json:"product_id"`
+	VariantID       int    `json:"variant_id"`
+	Currency        string `json:"currency"`
+	TechniqueKey    string `json:"technique_key"`
+	Price           string `json:"price"`
+	DiscountedPrice string `json:"discounted_price"`
+	DateRecorded    int64  `json:"date_recorded"`
+}
+
+type PriceChange struct {
+	ProductID     int    `json:"product_id"`
+	VariantID     int    `json:"variant_id"`
+	Currency      string `json:"currency"`
+	TechniqueKey  string `json:"technique_key"`
+	PreviousPrice string `json:"previous_price"`
+	Price         string `json:"price"`
+	DateRecorded  int64  `json:"date_recorded"`
+}
+
+func InsertPriceHistory(productID int, currency string, variant *printfulmodel.VariantsPriceData) error {
+	if printfulDb == nil {
+		return errors.New("database is not initialized. Did you forgot to init postgre ?")
+	}
+
+	now := time.Now().Unix()
+	for _, technique := range variant.Techniques {
+		_, err := printfulDb.Exec(`INSERT INTO variant_prices_history (product_id, variant_id, currency, technique_key, price, discounted_price, date_recorded)
+		VALUES ($1, $2, $3, $4, $5, $6, $7)`,
+			productID,
+			variant.ID,
+			currency,
+			technique.TechniqueKey,
+			technique.Price,
+			technique.DiscountedPrice,
+			now,
+		)
+
+		if err != nil {
+			return fmt.Errorf("failed to insert price history "+strconv.Itoa(variant.ID)+" "+currency+" : <%w>", err)
+		}
+	}
+
+	return nil
+}
+
+// Returns the price history of a product, oldest first. If variantID is 0, all variants are returned
+func FindPriceHistory(productID int, variantID int, currency string) ([]PriceHistory, error) {
+	if printfulDb == nil {
+		return nil, errors.New("database is not initialized. Did you forgot to init postgre ?")
+	}
+
+	query := `SELECT product_id, variant_id, currency, technique_key, price, discounted_price, date_recorded FROM variant_prices_history WHERE product_id = $1 AND ($2 = 0 OR variant_id = $2) AND currency = $3 ORDER BY date_recorded, variant_id;`
+	res, err := printfulDb.Query(query, productID, variantID, currency)
+	if err != nil {
+		return nil, fmt.Errorf("failed to execute query "+query+"in FindPriceHistory: <%w>", err)
+	}
+	defer res.Close()
+
+	history := make([]PriceHistory, 0, 20)
+	for res.Next() {
+		entry := PriceHistory{}
+
+		err = res.Scan(&entry.ProductID, &entry.VariantID, &entry.Currency, &entry.TechniqueKey, &entry.Price, &entry.DiscountedPrice, &entry.DateRecorded)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan row in FindPriceHistory: <%w>", err)
+		}
+
+		history = append(history, entry)
+	}
+
+	if err := res.Err(); err != nil {
+		return nil, fmt.Errorf("failed to get next row in FindPriceHistory: <%w>", err)
+	}
+
+	return history, nil
+}
+
+// Returns the price changes recorded since a date whose relative variation exceeds threshold (in percent)
+func FindPriceChanges(currency string, since int64, threshold float64) ([]PriceChange, error) {
+	if printfulDb == nil {
+		return nil, errors.New("database is not initialized. Did you forgot to init postgre ?")
+	}
+
+	query := `SELECT product_id, variant_id, technique_key, previous_price, price, date_recorded FROM (
+		SELECT product_id, variant_id, technique_key, price, date_recorded,
+		LAG(price) OVER (PARTITION BY variant_id, technique_key ORDER BY date_recorded) AS previous_price
+		FROM variant_prices_history WHERE currency = $1
+	) AS history
+	WHERE previous_price IS NOT NULL
+	AND date_recorded >= $2
+	AND ABS(price::NUMERIC - previous_price::NUMERIC) * 100 > NULLIF(previous_price::NUMERIC, 0) * $3
+	ORDER BY date_recorded, product_id, variant_id;`
+	res, err := printfulDb.Query(query, currency, since, threshold)
+	if err != nil {
+		return nil, fmt.Errorf("failed to execute query "+query+"in FindPriceChanges: <%w>", err)
+	}
+	defer res.Close()
+
+	changes := make([]PriceChange, 0, 20)
+	for res.Next() {
+		change := PriceChange{Currency: currency}
+
+		err = res.Scan(&change.ProductID, &change.VariantID, &change.TechniqueKey, &change.PreviousPrice, &change.Price, &change.DateRecorded)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan row in FindPriceChanges: <%w>", err)
+		}
+
+		changes = append(changes, change)
+	}
+
+	if err := res.Err(); err != nil {
+		return nil, fmt.Errorf("failed to get next row in FindPriceChanges: <%w>", err)
+	}
+
+	return changes, nil
+}
@@ -12,8 +12,13 @@ var printfulDb *sql.DB
 var imagesDb *sql.DB
 var cacheMaxAge int64 = 86400
 
+// Replaces the previous database, if any. Cached rows belong to the previous database and are dropped
 func InitPrintfulDB(config config.Database) {
+	if printfulDb != nil {
+		printfulDb.Close()
+	}
 	printfulDb = openPostgre(config.Datasource)
+	clearCaches()
 }
 
 func InitImagesDB(config config.Database) {
@@ -0,0 +1,62 @@
+package database
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strconv"
+	"time"
+
+	printfulmodel "github.com/baldurstod/go-printful-sdk/model"
+)
+
+func InsertProductImages(productID int, productImages []printfulmodel.VariantImages) error {
+	if printfulDb == nil {
+		return errors.New("database is not initialized. Did you forgot to init postgre ?")
+	}
+
+	images, err := json.Marshal(&productImages)
+	if err != nil {
+		return fmt.Errorf("failed to marshal productImages: <%w>", err)
+	}
+
+	_, err = printfulDb.Exec(`INSERT INTO product_images (product_id, images, last_updated)
+	VALUES ($1, $2, $3)
+	ON CONFLICT (product_id) DO UPDATE SET
+	images = $2,
+	last_updated = $3`,
+		productID,
+		images,
+		time.Now().Unix(),
+	)
+
+	if err != nil {
+		return fmt.Errorf("failed to insert product images "+strconv.Itoa(productID)+" : <%w>", err)
+	}
+
+	return nil
+}
+
+func FindProductImages(productID int) ([]printfulmodel.VariantImages, bool, error) {
+	if printfulDb == nil {
+		return nil, false, errors.New("database is not initialized. Did you forgot to init postgre ?")
+	}
+
+	query := `SELECT images, last_updated FROM product_images WHERE product_id = $1;`
+	row := printfulDb.QueryRow(query, productID)
+
+	var productImages string
+	var lastUpdated int64
+
+	err := row.Scan(&productImages, &lastUpdated)
+	if err != nil {
+		return nil, false, fmt.Errorf("failed to scan row in FindProductImages: <%w>", err)
+	}
+
+	images := []printfulmodel.VariantImages{}
+	if err = json.Unmarshal([]byte(productImages), &images); err != nil {
+		return nil, false, err
+	}
+
+	return images, time.Now().Unix()-lastUpdated > cacheMaxAge, nil
+}
@@ -1,10 +1,12 @@
 package database
 
 import (
+	"database/sql"
 	"encoding/json"
 	"errors"
 	"fmt"
 	"reflect"
+	"slices"
 	"strconv"
 	"strings"
 	"time"
@@ -13,6 +15,14 @@ import (
 	"github.com/lib/pq"
 )
 
+var productsCache = newMemoryCache[int, []printfulmodel.Product]("products")
+var productCache = newMemoryCache[int, *printfulmodel.Product]("product")
+
+func invalidateProduct(productID int) {
+	productCache.invalidate(productID)
+	productsCache.clear()
+}
+
 func InsertProduct(product printfulmodel.Product) error {
 	if printfulDb == nil {
 		return errors.New("database is not initialized. Did you forgot to init postgre ?")
@@ -65,7 +75,6 @@ func InsertProduct(product printfulmodel.Product) error {
 	techniques = $15,
 	placements = $16,
 	product_options = $17,
-	date_created = $18,
 	date_updated = $19`,
 		product.ID,
 		product.MainCategoryID,
@@ -92,15 +101,29 @@ func InsertProduct(product printfulmodel.Product) error {
 		return fmt.Errorf("failed to insert product "+strconv.Itoa(product.ID)+" : <%w>", err)
 	}
 
+	invalidateProduct(product.ID)
+
 	return nil
 }
 
 func FindProducts() ([]printfulmodel.Product, error) {
+	products, _, err := cached(productsCache, 0, func() ([]printfulmodel.Product, int64, error) {
+		products, err := findProducts()
+		return products, 0, err
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return slices.Clone(products), nil
+}
+
+func findProducts() ([]printfulmodel.Product, error) {
 	if printfulDb == nil {
 		return nil, errors.New("database is not initialized. Did you forgot to init postgre ?")
 	}
 
-	query := `SELECT id, main_category_id, type, name, brand, model, image, variant_count, catalog_variant_ids, is_discontinued, description, sizes, colors, techniques, placements, product_options, date_created, date_updated FROM products;`
+	query := `SELECT id, main_category_id, type, name, brand, model, image, image_women, variant_count, catalog_variant_ids, is_discontinued, description, sizes, colors, techniques, placements, product_options, date_created, date_updated FROM products;`
 	res, err := printfulDb.Query(query)
 	if err != nil {
 		return nil, fmt.Errorf("failed to execute query "+query+"in FindProducts: <%w>", err)
@@ -116,6 +139,7 @@ func FindProducts() ([]printfulmodel.Product, error) {
 		var brand string
 		var model string
 		var image string
+		var imageWomen sql.NullString
 		var variantCount int
 		var catalogVariantIDs []int32
 		var isDiscontinued bool
@@ -128,7 +152,7 @@ func FindProducts() ([]printfulmodel.Product, error) {
 		var dateCreated time.Time
 		var dateUpdated time.Time
 
-		err = res.Scan(&id, &mainCategoryID, &productType, &name, &brand, &model, &image, &variantCount, pq.Array(&catalogVariantIDs), &isDiscontinued, &description, pq.Array(&sizes), &colors, &techniques, &placements, &productOptions, &dateCreated, &dateUpdated)
+		err = res.Scan(&id, &mainCategoryID, &productType, &name, &brand, &model, &image, &imageWomen, &variantCount, pq.Array(&catalogVariantIDs), &isDiscontinued, &description, pq.Array(&sizes), &colors, &techniques, &placements, &productOptions, &dateCreated, &dateUpdated)
 		if err != nil {
 			return nil, fmt.Errorf("failed to scan row in FindProducts: <%w>", err)
 		}
@@ -166,6 +190,7 @@ func FindProducts() ([]printfulmodel.Product, error) {
 			Brand:             brand,
 			Model:             model,
 			Image:             image,
+			ImageWomen:        imageWomen.String,
 			VariantCount:      variantCount,
 			CatalogVariantIDs: catalogVariantIDs2,
 			IsDiscontinued:    isDiscontinued,
@@ -188,11 +213,23 @@ func FindProducts() ([]printfulmodel.Product, error) {
 }
 
 func FindProduct(productID int) (*printfulmodel.Product, bool, error) {
+	product, outdated, err := cached(productCache, productID, func() (*printfulmodel.Product, int64, error) {
+		return findProduct(productID)
+	})
+	if err != nil {
+		return nil, false, err
+	}
+
+	p := *product
+	return &p, outdated, nil
+}
+
+func findProduct(productID int) (*printfulmodel.Product, int64, error) {
 	if printfulDb == nil {
-		return nil, false, errors.New("database is not initialized. Did you forgot to init postgre ?")
+		return nil, 0, errors.New("database is not initialized. Did you forgot to init postgre ?")
 	}
 
-	query := `SELECT id, main_category_id, type, name, brand, model, image, variant_count, catalog_variant_ids, is_discontinued, description, sizes, colors, techniques, placements, product_options, last_updated FROM products WHERE id = $1;`
+	query := `SELECT id, main_category_id, type, name, brand, model, image, image_women, variant_count, catalog_variant_ids, is_discontinued, description, sizes, colors, techniques, placements, product_options, date_updated FROM products WHERE id = $1;`
 	row := printfulDb.QueryRow(query, productID)
 
 	var id int
@@ -202,8 +239,9 @@ func FindProduct(productID int) (*printfulmodel.Product, bool, error) {
 	var brand string
 	var model string
 	var image string
+	var imageWomen sql.NullString
 	var variantCount int
-	var catalogVariantIDs []int
+	var catalogVariantIDs []int32
 	var isDiscontinued bool
 	var description string
 	var sizes []string
@@ -211,31 +249,36 @@ func FindProduct(productID int) (*printfulmodel.Product, bool, error) {
 	var techniques string
 	var placements string
 	var productOptions string
-	var lastUpdated int64
+	var dateUpdated time.Time
 
-	err := row.Scan(&id, &mainCategoryID, &productType, &name, &brand, &model, &image, &variantCount, pq.Array(&catalogVariantIDs), &isDiscontinued, &description, pq.Array(&sizes), &colors, &techniques, &placements, &productOptions, &lastUpdated)
+	err := row.Scan(&id, &mainCategoryID, &productType, &name, &brand, &model, &image, &imageWomen, &variantCount, pq.Array(&catalogVariantIDs), &isDiscontinued, &description, pq.Array(&sizes), &colors, &techniques, &placements, &productOptions, &dateUpdated)
 	if err != nil {
-		return nil, false, fmt.Errorf("failed to scan row in FindProduct: <%w>", err)
+		return nil, 0, fmt.Errorf("failed to scan row in FindProduct: <%w>", err)
 	}
 
 	jsonColors := []printfulmodel.Color{}
 	if err = json.Unmarshal([]byte(colors), &jsonColors); err != nil {
-		return nil, false, err
+		return nil, 0, err
 	}
 
 	jsonTechniques := []printfulmodel.Technique{}
 	if err = json.Unmarshal([]byte(techniques), &jsonTechniques); err != nil {
-		return nil, false, err
+		return nil, 0, err
 	}
 
 	jsonPlacements := []printfulmodel.ProductPlacement{}
 	if err = json.Unmarshal([]byte(placements), &jsonPlacements); err != nil {
-		return nil, false, err
+		return nil, 0, err
 	}
 
 	jsonProductOptions := []printfulmodel.CatalogOption{}
 	if err = json.Unmarshal([]byte(productOptions), &jsonProductOptions); err != nil {
-		return nil, false, err
+		return nil, 0, err
+	}
+
+	catalogVariantIDs2 := make([]int, len(catalogVariantIDs))
+	for i, i32 := range catalogVariantIDs {
+		catalogVariantIDs2[i] = int(i32)
 	}
 
 	product := printfulmodel.Product{
@@ -246,8 +289,9 @@ func FindProduct(productID int) (*printfulmodel.Product, bool, error) {
 		Brand:             brand,
 		Model:             model,
 		Image:             image,
+		ImageWomen:        imageWomen.String,
 		VariantCount:      variantCount,
-		CatalogVariantIDs: catalogVariantIDs,
+		CatalogVariantIDs: catalogVariantIDs2,
 		IsDiscontinued:    isDiscontinued,
 		Description:       description,
 		Sizes:             sizes,
@@ -257,7 +301,7 @@ func FindProduct(productID int) (*printfulmodel.Product, bool, error) {
 		ProductOptions:    jsonProductOptions,
 	}
 
-	return &product, time.Now().Unix()-lastUpdated > cacheMaxAge, nil
+	return &product, dateUpdated.Unix(), nil
 }
 
 func UpdateProductVariantIds(productID int, variantIds []int) error {
@@ -275,9 +319,48 @@ func UpdateProductVariantIds(productID int, variantIds []int) error {
 		return fmt.Errorf("failed to update product "+strconv.Itoa(productID)+" : <%w>", err)
 	}
 
+	invalidateProduct(productID)
+
 	return nil
 }
 
+// Returns true if the product is stored in the catalog
+func HasProduct(productID int) (bool, error) {
+	if printfulDb == nil {
+		return false, errors.New("database is not initialized. Did you forgot to init postgre ?")
+	}
+
+	var found bool
+	query := `SELECT EXISTS (SELECT 1 FROM products WHERE id = $1);`
+	if err := printfulDb.QueryRow(query, productID).Scan(&found); err != nil {
+		return false, fmt.Errorf("failed to scan row in HasProduct: <%w>", err)
+	}
+
+	return found, nil
+}
+
+func FindProductVariantIds(productID int) ([]int, error) {
+	if printfulDb == nil {
+		return nil, errors.New("database is not initialized. Did you forgot to init postgre ?")
+	}
+
+	query := `SELECT catalog_variant_ids FROM products WHERE id = $1;`
+	row := printfulDb.QueryRow(query, productID)
+
+	var catalogVariantIDs []int64
+	err := row.Scan(pq.Array(&catalogVariantIDs))
+	if err != nil {
+		return nil, fmt.Errorf("failed to scan row in FindProductVariantIds: <%w>", err)
+	}
+
+	variantIDs := make([]int, len(catalogVariantIDs))
+	for i, id := range catalogVariantIDs {
+		variantIDs[i] = int(id)
+	}
+
+	return variantIDs, nil
+}
+
 type UpdateProductFields struct {
 	MainCategoryID    bool
 	Categories        bool
@@ -375,5 +458,7 @@ func UpdateProduct(product printfulmodel.Product, fields UpdateProductFields) er
 		return fmt.Errorf("failed to update product: <%w>", err)
 	}
 
+	invalidateProduct(product.ID)
+
 	return nil
 }
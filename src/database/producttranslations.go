@@ -61,7 +61,7 @@ func FindProductTranslations(language string) ([]ProductTranslation, error) {
 		var description string
 		var lastUpdated int64
 
-		err = res.Scan(&name, &description, &lastUpdated)
+		err = res.Scan(&id, &name, &description, &lastUpdated)
 		if err != nil {
 			return nil, fmt.Errorf("failed to scan row in FindProductTranslations: <%w>", err)
 		}
@@ -0,0 +1,189 @@
+package database
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/lib/pq"
+)
+
+type RefreshRunStatus string
+
+const (
+	RefreshRunning   RefreshRunStatus = "running"
+	RefreshCompleted RefreshRunStatus = "completed"
+	RefreshFailed    RefreshRunStatus = "failed"
+	RefreshCancelled RefreshRunStatus = "cancelled"
+)
+
+type RefreshRun struct {
+	ID          int              `json:"id"`
+	Currency    string           `json:"currency"`
+	UseCache    bool             `json:"use_cache"`
+	Status      RefreshRunStatus `json:"status"`
+	Total       int              `json:"total"`
+	Done        int              `json:"done"`
+	Failed      int              `json:"failed"`
+	DateStarted int64            `json:"date_started"`
+	DateEnded   int64            `json:"date_ended"`
+}
+
+type RefreshStepResult struct {
+	RunID     int    `json:"run_id"`
+	ProductID int    `json:"product_id"`
+	Step      string `json:"step"`
+	Success   bool   `json:"success"`
+	// Pending steps have not run yet. They are not successful and can be retried
+	Pending     bool   `json:"pending"`
+	Error       string `json:"error,omitempty"`
+	DateUpdated int64  `json:"date_updated"`
+}
+
+func InsertRefreshRun(run *RefreshRun) (int, error) {
+	if printfulDb == nil {
+		return 0, errors.New("database is not initialized. Did you forgot to init postgre ?")
+	}
+
+	var id int
+	err := printfulDb.QueryRow(`INSERT INTO refresh_runs (currency, use_cache, status, total, done, failed, date_started, date_ended)
+	VALUES ($1, $2, $3, $4, $5, $6, $7, $8)
+	RETURNING id`,
+		run.Currency,
+		run.UseCache,
+		run.Status,
+		run.Total,
+		run.Done,
+		run.Failed,
+		run.DateStarted,
+		run.DateEnded,
+	).Scan(&id)
+
+	if err != nil {
+		return 0, fmt.Errorf("failed to insert refresh run : <%w>", err)
+	}
+
+	return id, nil
+}
+
+func UpdateRefreshRun(run *RefreshRun) error {
+	if printfulDb == nil {
+		return errors.New("database is not initialized. Did you forgot to init postgre ?")
+	}
+
+	_, err := printfulDb.Exec(`UPDATE refresh_runs SET status = $2, total = $3, done = $4, failed = $5, date_ended = $6 WHERE id = $1`,
+		run.ID,
+		run.Status,
+		run.Total,
+		run.Done,
+		run.Failed,
+		run.DateEnded,
+	)
+
+	if err != nil {
+		return fmt.Errorf("failed to update refresh run "+strconv.Itoa(run.ID)+" : <%w>", err)
+	}
+
+	return nil
+}
+
+// Returns a refresh run. If runID is 0, the latest run is returned
+func FindRefreshRun(runID int) (*RefreshRun, error) {
+	if printfulDb == nil {
+		return nil, errors.New("database is not initialized. Did you forgot to init postgre ?")
+	}
+
+	query := `SELECT id, currency, use_cache, status, total, done, failed, date_started, date_ended FROM refresh_runs WHERE $1 = 0 OR id = $1 ORDER BY id DESC LIMIT 1;`
+	row := printfulDb.QueryRow(query, runID)
+
+	run := RefreshRun{}
+	err := row.Scan(&run.ID, &run.Currency, &run.UseCache, &run.Status, &run.Total, &run.Done, &run.Failed, &run.DateStarted, &run.DateEnded)
+	if err != nil {
+		return nil, fmt.Errorf("failed to scan row in FindRefreshRun: <%w>", err)
+	}
+
+	return &run, nil
+}
+
+func InsertRefreshStepResult(result *RefreshStepResult) error {
+	if printfulDb == nil {
+		return errors.New("database is not initialized. Did you forgot to init postgre ?")
+	}
+
+	_, err := printfulDb.Exec(`INSERT INTO refresh_steps (run_id, product_id, step, success, pending, error, date_updated)
+	VALUES ($1, $2, $3, $4, $5, $6, $7)
+	ON CONFLICT (run_id, product_id, step) DO UPDATE SET
+	success = $4,
+	pending = $5,
+	error = $6,
+	date_updated = $7`,
+		result.RunID,
+		result.ProductID,
+		result.Step,
+		result.Success,
+		result.Pending,
+		result.Error,
+		time.Now().Unix(),
+	)
+
+	if err != nil {
+		return fmt.Errorf("failed to insert refresh step "+strconv.Itoa(result.RunID)+" "+strconv.Itoa(result.ProductID)+" "+result.Step+" : <%w>", err)
+	}
+
+	return nil
+}
+
+// Record every step of the products as pending, so steps that never run can be retried
+func InsertPendingRefreshSteps(runID int, productIDs []int, steps []string) error {
+	if printfulDb == nil {
+		return errors.New("database is not initialized. Did you forgot to init postgre ?")
+	}
+
+	_, err := printfulDb.Exec(`INSERT INTO refresh_steps (run_id, product_id, step, success, pending, error, date_updated)
+	SELECT $1, product_id, step, FALSE, TRUE, '', $4
+	FROM unnest($2::INTEGER[]) AS product_id CROSS JOIN unnest($3::TEXT[]) AS step
+	ON CONFLICT (run_id, product_id, step) DO NOTHING`,
+		runID,
+		pq.Array(productIDs),
+		pq.Array(steps),
+		time.Now().Unix(),
+	)
+
+	if err != nil {
+		return fmt.Errorf("failed to insert pending refresh steps "+strconv.Itoa(runID)+" : <%w>", err)
+	}
+
+	return nil
+}
+
+func FindRefreshStepResults(runID int, failedOnly bool) ([]RefreshStepResult, error) {
+	if printfulDb == nil {
+		return nil, errors.New("database is not initialized. Did you forgot to init postgre ?")
+	}
+
+	query := `SELECT run_id, product_id, step, success, pending, error, date_updated FROM refresh_steps WHERE run_id = $1 AND (NOT $2 OR NOT success) ORDER BY product_id, step;`
+	res, err := printfulDb.Query(query, runID, failedOnly)
+	if err != nil {
+		return nil, fmt.Errorf("failed to execute query "+query+"in FindRefreshStepResults: <%w>", err)
+	}
+	defer res.Close()
+
+	results := make([]RefreshStepResult, 0, 20)
+	for res.Next() {
+		result := RefreshStepResult{}
+
+		err = res.Scan(&result.RunID, &result.ProductID, &result.Step, &result.Success, &result.Pending, &result.Error, &result.DateUpdated)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan row in FindRefreshStepResults: <%w>", err)
+		}
+
+		results = append(results, result)
+	}
+
+	if err := res.Err(); err != nil {
+		return nil, fmt.Errorf("failed to get next row in FindRefreshStepResults: <%w>", err)
+	}
+
+	return results, nil
+}
@@ -0,0 +1,159 @@
+package database
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/lib/pq"
+)
+
+type ProductSearch struct {
+	Query        string
+	Language     string
+	Categories   []int
+	Technique    string
+	Placement    string
+	Brand        string
+	Color        string
+	Size         string
+	Discontinued *bool
+	Sort         string
+	Order        string // "asc", "desc" or empty for the default order of the sort
+	Offset       int
+	Limit        int
+}
+
+// Postgres text search configuration for each Printful language
+var searchConfigs = map[string]string{
+	"en_US": "english",
+	"en_GB": "english",
+	"en_CA": "english",
+	"es_ES": "spanish",
+	"fr_FR": "french",
+	"de_DE": "german",
+	"it_IT": "italian",
+	"ja_JP": "simple",
+}
+
+// Returns the ids of the products matching a search, in order, and the total number of matches
+func SearchProducts(search *ProductSearch) ([]int, int, error) {
+	if printfulDb == nil {
+		return nil, 0, errors.New("database is not initialized. Did you forgot to init postgre ?")
+	}
+
+	searchConfig, ok := searchConfigs[search.Language]
+	if !ok {
+		searchConfig = "simple"
+	}
+
+	queryParams := []any{search.Language, searchConfig}
+	conditions := make([]string, 0)
+
+	addCondition := func(condition string, value any) {
+		param := "$" + strconv.Itoa(len(queryParams)+1)
+
+		conditions = append(conditions, strings.ReplaceAll(condition, "$?", param))
+		queryParams = append(queryParams, value)
+	}
+
+	// Documents are stored in search_document and indexed, products without a translation fall back to the english one
+	rank := "0"
+	if search.Query != "" {
+		addCondition(`(t.search_document @@ websearch_to_tsquery($2::REGCONFIG, $?) OR (t.search_document IS NULL AND p.search_document @@ websearch_to_tsquery('english', $?)))`, search.Query)
+		queryParam := "$" + strconv.Itoa(len(queryParams))
+		rank = `CASE WHEN t.search_document IS NULL THEN ts_rank(p.search_document, websearch_to_tsquery('english', ` + queryParam + `)) ELSE ts_rank(t.search_document, websearch_to_tsquery($2::REGCONFIG, ` + queryParam + `)) END`
+	}
+
+	if len(search.Categories) > 0 {
+		addCondition(`(p.main_category_id = ANY($?::INTEGER[]) OR p.categories && $?::INTEGER[])`, pq.Array(search.Categories))
+	}
+
+	if search.Technique != "" {
+		addCondition(`p.techniques @> jsonb_build_array(jsonb_build_object('key', $?::TEXT))`, search.Technique)
+	}
+
+	if search.Placement != "" {
+		addCondition(`p.placements @> jsonb_build_array(jsonb_build_object('placement', $?::TEXT))`, search.Placement)
+	}
+
+	if search.Brand != "" {
+		addCondition(`LOWER(p.brand) = LOWER($?)`, search.Brand)
+	}
+
+	if search.Color != "" {
+		addCondition(`EXISTS (SELECT 1 FROM jsonb_array_elements(p.colors) AS c WHERE LOWER(c->>'name') = LOWER($?))`, search.Color)
+	}
+
+	if search.Size != "" {
+		addCondition(`$? = ANY(p.sizes)`, search.Size)
+	}
+
+	if search.Discontinued != nil {
+		addCondition(`p.is_discontinued = $?`, *search.Discontinued)
+	}
+
+	where := ""
+	if len(conditions) > 0 {
+		where = "WHERE " + strings.Join(conditions, " AND ")
+	}
+
+	var orderBy string
+	order := search.Order
+	switch search.Sort {
+	case "name":
+		orderBy = "COALESCE(t.name, p.name)"
+	case "newest":
+		orderBy = "p.date_created"
+	case "id":
+		orderBy = "p.id"
+	default:
+		orderBy = "rank"
+		// Best matches first by default
+		if order == "" {
+			order = "desc"
+		}
+	}
+	if order == "desc" {
+		orderBy += " DESC"
+	}
+
+	limit := search.Limit
+	if limit <= 0 {
+		limit = 50
+	}
+
+	query := `SELECT p.id, COUNT(*) OVER() AS total, ` + rank + ` AS rank
+	FROM products AS p
+	LEFT JOIN product_translations AS t ON t.product_id = p.id AND t.language = $1
+	` + where + `
+	ORDER BY ` + orderBy + `, p.id
+	LIMIT ` + strconv.Itoa(limit) + ` OFFSET ` + strconv.Itoa(max(search.Offset, 0)) + `;`
+
+	res, err := printfulDb.Query(query, queryParams...)
+	if err != nil {
+		return nil, 0, fmt.Errorf("failed to execute query "+query+"in SearchProducts: <%w>", err)
+	}
+	defer res.Close()
+
+	ids := make([]int, 0, limit)
+	total := 0
+	for res.Next() {
+		var id int
+		var rank float64
+
+		err = res.Scan(&id, &total, &rank)
+		if err != nil {
+			return nil, 0, fmt.Errorf("failed to scan row in SearchProducts: <%w>", err)
+		}
+
+		ids = append(ids, id)
+	}
+
+	if err := res.Err(); err != nil {
+		return nil, 0, fmt.Errorf("failed to get next row in SearchProducts: <%w>", err)
+	}
+
+	return ids, total, nil
+}
@@ -0,0 +1,99 @@
+package database
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"time"
+)
+
+type TaggedImage struct {
+	ProductID     int    `json:"product_id"`
+	Tag           string `json:"tag"`
+	ImageURL      string `json:"image_url"`
+	Color         string `json:"color"`
+	MockupStyleID int    `json:"mockup_style_id"`
+	LastUpdated   int64  `json:"last_updated"`
+}
+
+// Replace all the tagged images of a product. The women image is also stored in the product for backward compatibility,
+// it is cleared when images has none
+func ReplaceTaggedImages(productID int, images []TaggedImage) error {
+	if printfulDb == nil {
+		return errors.New("database is not initialized. Did you forgot to init postgre ?")
+	}
+
+	tx, err := printfulDb.Begin()
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction in ReplaceTaggedImages: <%w>", err)
+	}
+	defer tx.Rollback()
+
+	if _, err = tx.Exec(`DELETE FROM product_tagged_images WHERE product_id = $1`, productID); err != nil {
+		return fmt.Errorf("failed to delete tagged images "+strconv.Itoa(productID)+" : <%w>", err)
+	}
+
+	imageWomen := ""
+	now := time.Now().Unix()
+	for _, image := range images {
+		_, err = tx.Exec(`INSERT INTO product_tagged_images (product_id, tag, image_url, color, mockup_style_id, last_updated)
+		VALUES ($1, $2, $3, $4, $5, $6)`,
+			productID,
+			image.Tag,
+			image.ImageURL,
+			image.Color,
+			image.MockupStyleID,
+			now,
+		)
+		if err != nil {
+			return fmt.Errorf("failed to insert tagged image "+strconv.Itoa(productID)+" "+image.Tag+" : <%w>", err)
+		}
+
+		if image.Tag == "women" {
+			imageWomen = image.ImageURL
+		}
+	}
+
+	if _, err = tx.Exec(`UPDATE products SET image_women = $1 WHERE id = $2`, imageWomen, productID); err != nil {
+		return fmt.Errorf("failed to update product "+strconv.Itoa(productID)+" : <%w>", err)
+	}
+
+	if err = tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit transaction in ReplaceTaggedImages: <%w>", err)
+	}
+
+	invalidateProduct(productID)
+
+	return nil
+}
+
+func FindTaggedImages(productID int) ([]TaggedImage, error) {
+	if printfulDb == nil {
+		return nil, errors.New("database is not initialized. Did you forgot to init postgre ?")
+	}
+
+	query := `SELECT product_id, tag, image_url, color, mockup_style_id, last_updated FROM product_tagged_images WHERE product_id = $1 ORDER BY tag;`
+	res, err := printfulDb.Query(query, productID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to execute query "+query+"in FindTaggedImages: <%w>", err)
+	}
+	defer res.Close()
+
+	images := make([]TaggedImage, 0, 5)
+	for res.Next() {
+		image := TaggedImage{}
+
+		err = res.Scan(&image.ProductID, &image.Tag, &image.ImageURL, &image.Color, &image.MockupStyleID, &image.LastUpdated)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan row in FindTaggedImages: <%w>", err)
+		}
+
+		images = append(images, image)
+	}
+
+	if err := res.Err(); err != nil {
+		return nil, fmt.Errorf("failed to get next row in FindTaggedImages: <%w>", err)
+	}
+
+	return images, nil
+}
@@ -4,13 +4,16 @@ import (
 	"encoding/json"
 	"errors"
 	"fmt"
-	"log"
+	"slices"
 	"strconv"
 	"time"
 
 	printfulmodel "github.com/baldurstod/go-printful-sdk/model"
 )
 
+var variantsCache = newMemoryCache[int, []printfulmodel.Variant]("variants")
+var variantCache = newMemoryCache[int, *printfulmodel.Variant]("variant")
+
 func InsertVariant(variant *printfulmodel.Variant) error {
 	if printfulDb == nil {
 		return errors.New("database is not initialized. Did you forgot to init postgre ?")
@@ -49,20 +52,33 @@ func InsertVariant(variant *printfulmodel.Variant) error {
 		return fmt.Errorf("failed to insert variant "+strconv.Itoa(variant.ID)+" : <%w>", err)
 	}
 
+	variantsCache.invalidate(variant.CatalogProductID)
+	variantCache.invalidate(variant.ID)
+
 	return nil
 }
 
-func FindVariants(productID int) (variants []printfulmodel.Variant, outdated bool, err error) {
-	if printfulDb == nil {
-		return nil, false, errors.New("database is not initialized. Did you forgot to init postgre ?")
+func FindVariants(productID int) ([]printfulmodel.Variant, bool, error) {
+	variants, outdated, err := cached(variantsCache, productID, func() ([]printfulmodel.Variant, int64, error) {
+		return findVariants(productID)
+	})
+	if err != nil {
+		return nil, false, err
 	}
 
-	outdated = false
+	return slices.Clone(variants), outdated, nil
+}
+
+// Returns the last update of the oldest variant
+func findVariants(productID int) (variants []printfulmodel.Variant, lastUpdated int64, err error) {
+	if printfulDb == nil {
+		return nil, 0, errors.New("database is not initialized. Did you forgot to init postgre ?")
+	}
 
 	query := `SELECT id, name, catalog_product_id, color, color_code, color_code2, image, size, availability, last_updated FROM variants WHERE catalog_product_id = $1;`
 	res, err := printfulDb.Query(query, productID)
 	if err != nil {
-		return nil, false, fmt.Errorf("failed to execute query "+query+"in FindVariants: <%w>", err)
+		return nil, 0, fmt.Errorf("failed to execute query "+query+"in FindVariants: <%w>", err)
 	}
 	defer res.Close()
 
@@ -77,12 +93,18 @@ func FindVariants(productID int) (variants []printfulmodel.Variant, outdated boo
 		var image string
 		var size string
 		var availability string
-		var lastUpdated int64
+		var variantUpdated int64
 
-		err = res.Scan(&id, &name, &catalogProductID, &color, &colorCode, &colorCode2, &image, &size, &availability, &lastUpdated)
+		err = res.Scan(&id, &name, &catalogProductID, &color, &colorCode, &colorCode2, &image, &size, &availability, &variantUpdated)
 		if err != nil {
-			return nil, false, fmt.Errorf("failed to scan row in FindVariants: <%w>", err)
+			return nil, 0, fmt.Errorf("failed to scan row in FindVariants: <%w>", err)
+		}
+
+		jsonAvailability := []printfulmodel.Availability{}
+		if err = json.Unmarshal([]byte(availability), &jsonAvailability); err != nil {
+			return nil, 0, err
 		}
+
 		variant := printfulmodel.Variant{
 			ID:               id,
 			Name:             name,
@@ -92,28 +114,38 @@ func FindVariants(productID int) (variants []printfulmodel.Variant, outdated boo
 			ColorCode2:       colorCode2,
 			Image:            image,
 			Size:             size,
-			//Availability:     availability,
+			Availability:     jsonAvailability,
 		}
 
-		if time.Now().Unix()-(lastUpdated) > cacheMaxAge {
-			outdated = true
+		if lastUpdated == 0 || variantUpdated < lastUpdated {
+			lastUpdated = variantUpdated
 		}
 
-		log.Println("todo: availability")
-
 		variants = append(variants, variant)
 	}
 
 	if err := res.Err(); err != nil {
-		return nil, false, fmt.Errorf("failed to get next row in FindVariants: <%w>", err)
+		return nil, 0, fmt.Errorf("failed to get next row in FindVariants: <%w>", err)
 	}
 
-	return variants, outdated, nil
+	return variants, lastUpdated, nil
 }
 
 func FindVariant(variantID int) (*printfulmodel.Variant, bool, error) {
+	variant, outdated, err := cached(variantCache, variantID, func() (*printfulmodel.Variant, int64, error) {
+		return findVariant(variantID)
+	})
+	if err != nil {
+		return nil, false, err
+	}
+
+	v := *variant
+	return &v, outdated, nil
+}
+
+func findVariant(variantID int) (*printfulmodel.Variant, int64, error) {
 	if printfulDb == nil {
-		return nil, false, errors.New("database is not initialized. Did you forgot to init postgre ?")
+		return nil, 0, errors.New("database is not initialized. Did you forgot to init postgre ?")
 	}
 
 	query := `SELECT id, name, catalog_product_id, color, color_code, color_code2, image, size, availability, last_updated FROM variants WHERE id = $1;`
@@ -132,7 +164,12 @@ func FindVariant(variantID int) (*printfulmodel.Variant, bool, error) {
 
 	err := row.Scan(&id, &name, &catalogProductID, &color, &colorCode, &colorCode2, &image, &size, &availability, &lastUpdated)
 	if err != nil {
-		return nil, false, fmt.Errorf("failed to scan row in FindProduct: <%w>", err)
+		return nil, 0, fmt.Errorf("failed to scan row in FindProduct: <%w>", err)
+	}
+
+	jsonAvailability := []printfulmodel.Availability{}
+	if err = json.Unmarshal([]byte(availability), &jsonAvailability); err != nil {
+		return nil, 0, err
 	}
 
 	variant := printfulmodel.Variant{
@@ -144,8 +181,8 @@ func FindVariant(variantID int) (*printfulmodel.Variant, bool, error) {
 		ColorCode2:       colorCode2,
 		Image:            image,
 		Size:             size,
-		//Availability:     availability,
+		Availability:     jsonAvailability,
 	}
 
-	return &variant, time.Now().Unix()-lastUpdated > cacheMaxAge, nil
+	return &variant, lastUpdated, nil
 }
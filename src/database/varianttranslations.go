@@ -0,0 +1,102 @@
+package database
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"time"
+
+	printfulmodel "github.com/baldurstod/go-printful-sdk/model"
+)
+
+type VariantTranslation struct {
+	ID          int    `json:"id" bson:"id" mapstructure:"id"`
+	Language    string `json:"language" bson:"language" mapstructure:"language"`
+	Name        string `json:"name" bson:"name" mapstructure:"name"`
+	Color       string `json:"color" bson:"color" mapstructure:"color"`
+	Size        string `json:"size" bson:"size" mapstructure:"size"`
+	LastUpdated int64  `json:"last_updated" bson:"last_updated"`
+}
+
+func InsertVariantTranslation(language string, variant *printfulmodel.Variant) error {
+	if printfulDb == nil {
+		return errors.New("database is not initialized. Did you forgot to init postgre ?")
+	}
+
+	_, err := printfulDb.Exec(`INSERT INTO variant_translations (variant_id, language, catalog_product_id, name, color, size, last_updated)
+	VALUES ($1, $2, $3, $4, $5, $6, $7)
+	ON CONFLICT (variant_id, language) DO UPDATE SET
+	catalog_product_id = $3,
+	name = $4,
+	color = $5,
+	size = $6,
+	last_updated = $7`,
+		variant.ID,
+		language,
+		variant.CatalogProductID,
+		variant.Name,
+		variant.Color,
+		variant.Size,
+		time.Now().Unix(),
+	)
+
+	if err != nil {
+		return fmt.Errorf("failed to insert variant translation "+strconv.Itoa(variant.ID)+" "+language+" : <%w>", err)
+	}
+
+	return nil
+}
+
+func FindVariantTranslations(productID int, language string) ([]VariantTranslation, bool, error) {
+	if printfulDb == nil {
+		return nil, false, errors.New("database is not initialized. Did you forgot to init postgre ?")
+	}
+
+	query := `SELECT variant_id, name, color, size, last_updated FROM variant_translations WHERE catalog_product_id = $1 AND language = $2;`
+	res, err := printfulDb.Query(query, productID, language)
+	if err != nil {
+		return nil, false, fmt.Errorf("failed to execute query "+query+"in FindVariantTranslations: <%w>", err)
+	}
+	defer res.Close()
+
+	outdated := false
+	variantTranslations := make([]VariantTranslation, 0, 20)
+	for res.Next() {
+		variantTranslation := VariantTranslation{Language: language}
+
+		err = res.Scan(&variantTranslation.ID, &variantTranslation.Name, &variantTranslation.Color, &variantTranslation.Size, &variantTranslation.LastUpdated)
+		if err != nil {
+			return nil, false, fmt.Errorf("failed to scan row in FindVariantTranslations: <%w>", err)
+		}
+
+		if time.Now().Unix()-variantTranslation.LastUpdated > cacheMaxAge {
+			outdated = true
+		}
+
+		variantTranslations = append(variantTranslations, variantTranslation)
+	}
+
+	if err := res.Err(); err != nil {
+		return nil, false, fmt.Errorf("failed to get next row in FindVariantTranslations: <%w>", err)
+	}
+
+	return variantTranslations, outdated, nil
+}
+
+func FindVariantTranslation(variantID int, language string) (*VariantTranslation, error) {
+	if printfulDb == nil {
+		return nil, errors.New("database is not initialized. Did you forgot to init postgre ?")
+	}
+
+	query := `SELECT name, color, size, last_updated FROM variant_translations WHERE variant_id = $1 AND language = $2;`
+	row := printfulDb.QueryRow(query, variantID, language)
+
+	variantTranslation := VariantTranslation{ID: variantID, Language: language}
+
+	err := row.Scan(&variantTranslation.Name, &variantTranslation.Color, &variantTranslation.Size, &variantTranslation.LastUpdated)
+	if err != nil {
+		return nil, fmt.Errorf("failed to scan row in FindVariantTranslation: <%w>", err)
+	}
+
+	return &variantTranslation, nil
+}
@@ -2,6 +2,7 @@ package main
 
 import (
 	"encoding/json"
+	"go-printful-api/src/api"
 	"go-printful-api/src/config"
 	"go-printful-api/src/database"
 	"go-printful-api/src/printful"
@@ -16,6 +17,7 @@ func main() {
 	if content, err := os.ReadFile("config.json"); err == nil {
 		if err = json.Unmarshal(content, &config); err == nil {
 			printful.SetPrintfulConfig(config.Printful)
+			api.SetApiConfig(config.Api)
 			database.InitPrintfulDB(config.Databases.Printful)
 			database.InitImagesDB(config.Databases.Images)
 			defer database.ClosePostgre()
@@ -1,5 +1,130 @@
 package requests
 
+// Optional pagination and field selection of list actions
+type ListRequest struct {
+	Cursor string   `mapstructure:"cursor"`
+	Limit  int      `mapstructure:"limit" validate:"gte=0"`
+	Fields []string `mapstructure:"fields"`
+}
+
+type EmptyRequest struct{}
+
+type GetCategoriesRequest struct {
+	Language    string `mapstructure:"language" default:"en_US" fallback:"language"`
+	ListRequest `mapstructure:",squash"`
+}
+
+type GetCountriesRequest struct {
+	ListRequest `mapstructure:",squash"`
+}
+
+type GetProductsRequest struct {
+	Language    string `mapstructure:"language" default:"en_US" fallback:"language"`
+	ListRequest `mapstructure:",squash"`
+}
+
+type GetProductRequest struct {
+	ProductID int    `mapstructure:"product_id" validate:"required,gt=0"`
+	Language  string `mapstructure:"language" default:"en_US" fallback:"language"`
+}
+
+type GetProductV2Request struct {
+	ProductID   int    `mapstructure:"product_id" validate:"required,gt=0"`
+	Language    string `mapstructure:"language" default:"en_US" fallback:"language"`
+	CountryCode string `mapstructure:"country_code" validate:"omitempty,iso3166_1_alpha2"`
+}
+
+type GetProductPricesRequest struct {
+	ProductID int    `mapstructure:"product_id" validate:"required,gt=0"`
+	Currency  string `mapstructure:"currency" validate:"omitempty,iso4217"`
+}
+
+type GetPriceHistoryRequest struct {
+	ProductID int    `mapstructure:"product_id" validate:"required,gt=0"`
+	Currency  string `mapstructure:"currency" validate:"omitempty,iso4217"`
+	VariantID int    `mapstructure:"variant_id" validate:"gte=0"`
+}
+
+type GetPriceChangesRequest struct {
+	Currency string `mapstructure:"currency" validate:"omitempty,iso4217"`
+	Days     int    `mapstructure:"days" default:"7" validate:"gt=0"`
+}
+
+type GetCatalogChangesRequest struct {
+	Since int64 `mapstructure:"since" validate:"gte=0"`
+}
+
+type RefreshRunRequest struct {
+	RunID int `mapstructure:"run_id" validate:"gte=0"`
+}
+
+type RefreshProductRequest struct {
+	ProductID int `mapstructure:"product_id" validate:"required,gt=0"`
+}
+
+type GetVariantRequest struct {
+	VariantID int    `mapstructure:"variant_id" validate:"required,gt=0"`
+	Language  string `mapstructure:"language" default:"en_US" fallback:"language"`
+}
+
+type GetAvailableVariantsRequest struct {
+	ProductID   int    `mapstructure:"product_id" validate:"required,gt=0"`
+	CountryCode string `mapstructure:"country_code" validate:"required,iso3166_1_alpha2"`
+	Language    string `mapstructure:"language" default:"en_US" fallback:"language"`
+}
+
+type SimilarVariantsPlacement struct {
+	Placement   string `mapstructure:"placement" validate:"required"`
+	Technique   string `mapstructure:"technique"`
+	Orientation string `mapstructure:"orientation"`
+}
+
+type GetSimilarVariantsRequest struct {
+	VariantID  int                        `mapstructure:"variant_id" validate:"required,gt=0"`
+	Placements []SimilarVariantsPlacement `mapstructure:"placements" validate:"required,dive"`
+}
+
+type ProductIDRequest struct {
+	ProductID int `mapstructure:"product_id" validate:"required,gt=0"`
+}
+
+type GetProductImagesRequest struct {
+	ProductID     int    `mapstructure:"product_id" validate:"required,gt=0"`
+	Color         string `mapstructure:"color"`
+	MockupStyleID int    `mapstructure:"mockup_style_id" validate:"gte=0"`
+}
+
+type GetSyncProductRequest struct {
+	SyncProductID int64 `mapstructure:"sync_product_id" validate:"required,gt=0"`
+}
+
 type AddImagesRequest struct {
-	Images []string `mapstructure:"images"`
+	Images []string `mapstructure:"images" validate:"required,min=1,dive,required"`
+}
+
+type SearchProductsRequest struct {
+	Query        string `mapstructure:"query"`
+	Language     string `mapstructure:"language" default:"en_US" fallback:"language"`
+	Categories   []int  `mapstructure:"categories"`
+	Technique    string `mapstructure:"technique"`
+	Placement    string `mapstructure:"placement"`
+	Brand        string `mapstructure:"brand"`
+	Color        string `mapstructure:"color"`
+	Size         string `mapstructure:"size"`
+	Discontinued *bool  `mapstructure:"discontinued"`
+	Sort         string `mapstructure:"sort" validate:"omitempty,oneof=relevance name newest id"`
+	Order        string `mapstructure:"order" validate:"omitempty,oneof=asc desc"`
+	Offset       int    `mapstructure:"offset" validate:"gte=0"`
+	Limit        int    `mapstructure:"limit" validate:"gte=0"`
+}
+
+type CreateApiKeyRequest struct {
+	Name           string   `mapstructure:"name" validate:"required"`
+	StoreID        string   `mapstructure:"store_id"`
+	Scopes         []string `mapstructure:"scopes" validate:"required,min=1,dive,oneof=catalog:read images:write orders:write admin"`
+	AllowedOrigins []string `mapstructure:"allowed_origins" validate:"dive,url"`
+}
+
+type RevokeApiKeyRequest struct {
+	ID int `mapstructure:"id" validate:"required,gt=0"`
 }
@@ -0,0 +1,59 @@
+package printful
+
+import (
+	"go-printful-api/src/apierrors"
+	"go-printful-api/src/database"
+	"slices"
+	"strings"
+
+	printfulmodel "github.com/baldurstod/go-printful-sdk/model"
+)
+
+// Stock statuses allowing a variant to be fulfilled
+var availableStatuses = []string{"in_stock", "stocked_on_demand"}
+
+// Returns the variants of a product which can be fulfilled in a country
+func GetAvailableVariants(storeID string, productID int, countryCode string, language string) ([]printfulmodel.Variant, error) {
+	countries, err := database.FindCountries()
+	if err != nil {
+		return nil, err
+	}
+
+	idx := slices.IndexFunc(countries, func(c printfulmodel.Country) bool { return strings.EqualFold(c.Code, countryCode) })
+	if idx == -1 {
+		return nil, apierrors.Validation("unknown country "+countryCode, nil)
+	}
+	country := &countries[idx]
+
+	variants, err := GetVariants(storeID, productID, language)
+	if err != nil {
+		return nil, err
+	}
+
+	available := make([]printfulmodel.Variant, 0, len(variants))
+	for _, variant := range variants {
+		if isVariantAvailable(&variant, country) {
+			available = append(available, variant)
+		}
+	}
+
+	return available, nil
+}
+
+// Availability regions are matched against the country name, code or region. The worldwide status is used if no region matches
+func isVariantAvailable(variant *printfulmodel.Variant, country *printfulmodel.Country) bool {
+	status := ""
+	for _, availability := range variant.Availability {
+		region := availability.Region
+		if strings.EqualFold(region, country.Name) || strings.EqualFold(region, country.Code) || strings.EqualFold(region, country.Region) {
+			status = availability.Status
+			break
+		}
+
+		if strings.EqualFold(region, "worldwide") {
+			status = availability.Status
+		}
+	}
+
+	return slices.Contains(availableStatuses, status)
+}
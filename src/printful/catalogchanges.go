@@ -0,0 +1,178 @@
+package printful
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"go-printful-api/src/database"
+	"slices"
+
+	printfulmodel "github.com/baldurstod/go-printful-sdk/model"
+)
+
+// Compare the products returned by Printful with the stored catalog and record the differences
+func recordProductChanges(products []printfulmodel.Product) error {
+	storedProducts, err := database.FindProducts()
+	if err != nil {
+		return fmt.Errorf("error in recordProductChanges: %w", err)
+	}
+
+	// Nothing to compare with on the first refresh, the whole catalog would be reported as added
+	if len(storedProducts) == 0 {
+		return nil
+	}
+
+	previousProducts := make(map[int]*printfulmodel.Product, len(storedProducts))
+	for i := range storedProducts {
+		previousProducts[storedProducts[i].ID] = &storedProducts[i]
+	}
+
+	changes := make([]database.CatalogChange, 0)
+	for _, product := range products {
+		previous, found := previousProducts[product.ID]
+		if !found {
+			changes = append(changes, database.CatalogChange{ProductID: product.ID, ChangeType: database.ProductAdded})
+			continue
+		}
+		delete(previousProducts, product.ID)
+
+		changes = append(changes, productChanges(previous, &product)...)
+	}
+
+	// Products no longer returned by Printful are kept but flagged as discontinued
+	for _, previous := range previousProducts {
+		if previous.IsDiscontinued {
+			continue
+		}
+		changes = append(changes, database.CatalogChange{ProductID: previous.ID, ChangeType: database.ProductRemoved})
+
+		previous.IsDiscontinued = true
+		if err = database.UpdateProduct(*previous, database.UpdateProductFields{IsDiscontinued: true}); err != nil {
+			return fmt.Errorf("error in recordProductChanges: %w", err)
+		}
+	}
+
+	if err = insertCatalogChanges(changes); err != nil {
+		return fmt.Errorf("error in recordProductChanges: %w", err)
+	}
+
+	return nil
+}
+
+// Compare a single product with its stored version and record the differences
+func recordProductChange(product *printfulmodel.Product) error {
+	var changes []database.CatalogChange
+
+	previous, _, err := database.FindProduct(product.ID)
+	switch {
+	case errors.Is(err, sql.ErrNoRows):
+		changes = []database.CatalogChange{{ProductID: product.ID, ChangeType: database.ProductAdded}}
+	case err != nil:
+		return fmt.Errorf("error in recordProductChange: %w", err)
+	default:
+		changes = productChanges(previous, product)
+	}
+
+	if err = insertCatalogChanges(changes); err != nil {
+		return fmt.Errorf("error in recordProductChange: %w", err)
+	}
+
+	return nil
+}
+
+// Returns the differences between a stored product and the product returned by Printful
+func productChanges(previous *printfulmodel.Product, product *printfulmodel.Product) []database.CatalogChange {
+	changes := make([]database.CatalogChange, 0)
+
+	if product.IsDiscontinued && !previous.IsDiscontinued {
+		changes = append(changes, database.CatalogChange{ProductID: product.ID, ChangeType: database.ProductDiscontinued})
+	}
+
+	if added, removed := diffKeys(placementKeys(previous.Placements), placementKeys(product.Placements)); len(added) > 0 || len(removed) > 0 {
+		changes = append(changes, database.CatalogChange{ProductID: product.ID, ChangeType: database.PlacementsChanged, Details: map[string]any{"added": added, "removed": removed}})
+	}
+
+	if added, removed := diffKeys(techniqueKeys(previous.Techniques), techniqueKeys(product.Techniques)); len(added) > 0 || len(removed) > 0 {
+		changes = append(changes, database.CatalogChange{ProductID: product.ID, ChangeType: database.TechniquesChanged, Details: map[string]any{"added": added, "removed": removed}})
+	}
+
+	return changes
+}
+
+func insertCatalogChanges(changes []database.CatalogChange) error {
+	for _, change := range changes {
+		if err := database.InsertCatalogChange(&change); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func recordVariantChanges(productID int, previousVariantIDs []int, variantIDs []int) error {
+	// New products are already reported as a whole
+	if len(previousVariantIDs) == 0 {
+		return nil
+	}
+
+	added, removed := diffKeys(previousVariantIDs, variantIDs)
+
+	for _, variantID := range added {
+		if err := database.InsertCatalogChange(&database.CatalogChange{ProductID: productID, VariantID: variantID, ChangeType: database.VariantAdded}); err != nil {
+			return fmt.Errorf("error in recordVariantChanges: %w", err)
+		}
+	}
+
+	for _, variantID := range removed {
+		if err := database.InsertCatalogChange(&database.CatalogChange{ProductID: productID, VariantID: variantID, ChangeType: database.VariantRemoved}); err != nil {
+			return fmt.Errorf("error in recordVariantChanges: %w", err)
+		}
+	}
+
+	return nil
+}
+
+func placementKeys(placements []printfulmodel.ProductPlacement) []string {
+	keys := make([]string, 0, len(placements))
+	for _, placement := range placements {
+		keys = append(keys, placement.Placement+"/"+placement.Technique)
+	}
+	return keys
+}
+
+func techniqueKeys(techniques []printfulmodel.Technique) []string {
+	keys := make([]string, 0, len(techniques))
+	for _, technique := range techniques {
+		keys = append(keys, technique.Key)
+	}
+	return keys
+}
+
+// Returns the keys present only in after (added) and only in before (removed)
+func diffKeys[K comparable](before []K, after []K) (added []K, removed []K) {
+	added = make([]K, 0)
+	removed = make([]K, 0)
+
+	for _, k := range after {
+		if !slices.Contains(before, k) {
+			added = append(added, k)
+		}
+	}
+
+	for _, k := range before {
+		if !slices.Contains(after, k) {
+			removed = append(removed, k)
+		}
+	}
+
+	return added, removed
+}
+
+func GetCatalogChanges(since int64) ([]database.CatalogChange, error) {
+	changes, err := database.FindCatalogChanges(since)
+	if err != nil {
+		return nil, fmt.Errorf("unable to find catalog changes: <%w>", err)
+	}
+
+	return changes, nil
+}
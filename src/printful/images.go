@@ -0,0 +1,170 @@
+package printful
+
+import (
+	"fmt"
+	"go-printful-api/src/config"
+	"go-printful-api/src/database"
+	"slices"
+	"strings"
+
+	printfulmodel "github.com/baldurstod/go-printful-sdk/model"
+)
+
+// Look for women pictures for unisex products, used when no selection is configured
+var defaultImageSelections = []config.ImageSelection{
+	{
+		Tag:                      "women",
+		CategoryPrefix:           "Women's",
+		ExcludedCategoryPrefixes: []string{"Women's Lifestyle"},
+		ViewName:                 "Front",
+		PreferredColors:          []string{"White"},
+	},
+}
+
+func imageSelections() []config.ImageSelection {
+	if len(printfulConfig.ImageSelections) == 0 {
+		return defaultImageSelections
+	}
+	return printfulConfig.ImageSelections
+}
+
+// Store the product images and pick a tagged image for each configured selection
+func refreshImages(product printfulmodel.Product, useCache bool) error {
+	productImages, err := printfulClient.GetProductImages(product.ID)
+	if err != nil {
+		return sdkError("error in refreshImages", err)
+	}
+
+	if err = database.InsertProductImages(product.ID, productImages); err != nil {
+		return fmt.Errorf("error in refreshImages: %w", err)
+	}
+
+	styles, _, err := database.FindMockupStyles(product.ID)
+	if err != nil {
+		return err
+	}
+
+	images := make([]database.TaggedImage, 0, len(imageSelections()))
+	for _, selection := range imageSelections() {
+		if image := selectImage(styles, productImages, &selection); image != nil {
+			images = append(images, *image)
+		}
+	}
+
+	// Tags no longer selected are removed
+	if err = database.ReplaceTaggedImages(product.ID, images); err != nil {
+		return fmt.Errorf("error in refreshImages: %w", err)
+	}
+
+	return nil
+}
+
+// Returns the first image matching a selection, preferably in one of the preferred colors
+func selectImage(styles []printfulmodel.MockupStyles, productImages []printfulmodel.VariantImages, selection *config.ImageSelection) *database.TaggedImage {
+	var selected *database.TaggedImage
+	selectedRank := len(selection.PreferredColors)
+
+	for _, style := range styles {
+		for _, mockupStyle := range style.MockupStyles {
+			if !matchMockupStyle(&mockupStyle, selection) {
+				continue
+			}
+
+			// Once we found a suitable style, check for pictures
+			for _, productImage := range productImages {
+				rank := slices.Index(selection.PreferredColors, productImage.Color)
+				if rank == -1 {
+					rank = len(selection.PreferredColors)
+				}
+
+				if selected != nil && rank >= selectedRank {
+					continue
+				}
+
+				for _, image := range productImage.Images {
+					if image.MockupStyleId == mockupStyle.Id {
+						selected = &database.TaggedImage{
+							Tag:           selection.Tag,
+							ImageURL:      image.ImageUrl,
+							Color:         productImage.Color,
+							MockupStyleID: mockupStyle.Id,
+						}
+						selectedRank = rank
+						break
+					}
+				}
+
+				if selected != nil && selectedRank == 0 {
+					return selected
+				}
+			}
+		}
+	}
+
+	return selected
+}
+
+func matchMockupStyle(mockupStyle *printfulmodel.MockupStyle, selection *config.ImageSelection) bool {
+	if !strings.HasPrefix(mockupStyle.CategoryName, selection.CategoryPrefix) {
+		return false
+	}
+
+	for _, prefix := range selection.ExcludedCategoryPrefixes {
+		if strings.HasPrefix(mockupStyle.CategoryName, prefix) {
+			return false
+		}
+	}
+
+	return selection.ViewName == "" || mockupStyle.ViewName == selection.ViewName
+}
+
+func GetTaggedImages(productID int) ([]database.TaggedImage, error) {
+	images, err := database.FindTaggedImages(productID)
+	if err != nil {
+		return nil, fmt.Errorf("unable to find tagged images: <%w>", err)
+	}
+
+	return images, nil
+}
+
+// Returns the product images, optionally filtered by color and mockup style. Images without a matching style are dropped
+func GetProductImages(productID int, color string, mockupStyleID int) ([]printfulmodel.VariantImages, error) {
+	productImages, err := readThrough(productID, imagesKey(productID),
+		func() ([]printfulmodel.VariantImages, bool, error) { return database.FindProductImages(productID) },
+		func() error {
+			product, err := GetProduct(DefaultStoreID, productID)
+			if err != nil {
+				return err
+			}
+			return refreshImages(*product, false)
+		},
+	)
+
+	if err != nil {
+		return nil, err
+	}
+
+	filtered := make([]printfulmodel.VariantImages, 0, len(productImages))
+	for _, productImage := range productImages {
+		if color != "" && !strings.EqualFold(productImage.Color, color) {
+			continue
+		}
+
+		if mockupStyleID != 0 {
+			images := make([]printfulmodel.Image, 0, len(productImage.Images))
+			for _, image := range productImage.Images {
+				if image.MockupStyleId == mockupStyleID {
+					images = append(images, image)
+				}
+			}
+			if len(images) == 0 {
+				continue
+			}
+			productImage.Images = images
+		}
+
+		filtered = append(filtered, productImage)
+	}
+
+	return filtered, nil
+}
@@ -0,0 +1,213 @@
+package printful
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"go-printful-api/src/database"
+	"io"
+	"log"
+	"net/http"
+	"net/url"
+	"time"
+
+	printfulmodel "github.com/baldurstod/go-printful-sdk/model"
+)
+
+// A stalled download must not block a refresh worker
+var mirrorClient = &http.Client{Timeout: 60 * time.Second}
+
+const defaultMirrorMaxImageSize = 20 << 20
+
+func mirrorMaxImageSize() int64 {
+	if printfulConfig.MirrorMaxImageSize <= 0 {
+		return defaultMirrorMaxImageSize
+	}
+	return printfulConfig.MirrorMaxImageSize
+}
+
+// Images are identified by their upstream url: a new url means a new image
+func mirroredImageID(sourceURL string) string {
+	hash := sha256.Sum256([]byte(sourceURL))
+	return "printful_" + hex.EncodeToString(hash[:16])
+}
+
+// Download the images that are not mirrored yet into the image store
+func mirrorImages(ctx context.Context, sourceURLs ...string) error {
+	if !printfulConfig.MirrorImages {
+		return nil
+	}
+
+	errs := make([]error, 0)
+	for _, sourceURL := range sourceURLs {
+		if err := ctx.Err(); err != nil {
+			errs = append(errs, err)
+			break
+		}
+
+		if sourceURL == "" {
+			continue
+		}
+
+		_, found, err := database.FindMirroredImage(sourceURL)
+		if err != nil {
+			return fmt.Errorf("error in mirrorImages: %w", err)
+		}
+		if found {
+			continue
+		}
+
+		if err = mirrorImage(ctx, sourceURL); err != nil {
+			errs = append(errs, err)
+		}
+	}
+
+	return errors.Join(errs...)
+}
+
+func mirrorImage(ctx context.Context, sourceURL string) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, sourceURL, nil)
+	if err != nil {
+		return fmt.Errorf("unable to create request for image %s: %w", sourceURL, err)
+	}
+
+	resp, err := mirrorClient.Do(req)
+	if err != nil {
+		return fmt.Errorf("unable to download image %s: %w", sourceURL, err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("unable to download image %s: HTTP status code %d", sourceURL, resp.StatusCode)
+	}
+
+	maxSize := mirrorMaxImageSize()
+	if resp.ContentLength > maxSize {
+		return fmt.Errorf("unable to download image %s: size %d exceeds %d bytes", sourceURL, resp.ContentLength, maxSize)
+	}
+
+	// The announced length can't be trusted, read one more byte to detect larger bodies
+	data, err := io.ReadAll(io.LimitReader(resp.Body, maxSize+1))
+	if err != nil {
+		return fmt.Errorf("unable to read image %s: %w", sourceURL, err)
+	}
+	if int64(len(data)) > maxSize {
+		return fmt.Errorf("unable to download image %s: size exceeds %d bytes", sourceURL, maxSize)
+	}
+
+	imageID := mirroredImageID(sourceURL)
+	if err = database.UploadImageData(imageID, data); err != nil {
+		return fmt.Errorf("error in mirrorImage: %w", err)
+	}
+
+	if err = database.InsertMirroredImage(sourceURL, imageID); err != nil {
+		return fmt.Errorf("error in mirrorImage: %w", err)
+	}
+
+	return nil
+}
+
+// Mirror the images of a product, its variants and its mockup templates
+func mirrorProductImages(ctx context.Context, productID int) error {
+	if !printfulConfig.MirrorImages {
+		return nil
+	}
+
+	product, _, err := database.FindProduct(productID)
+	if err != nil {
+		return fmt.Errorf("error in mirrorProductImages: %w", err)
+	}
+
+	variants, _, err := database.FindVariants(productID)
+	if err != nil {
+		return fmt.Errorf("error in mirrorProductImages: %w", err)
+	}
+
+	templates, _, err := database.FindMockupTemplates(productID)
+	if err != nil {
+		return fmt.Errorf("error in mirrorProductImages: %w", err)
+	}
+
+	sourceURLs := []string{product.Image, product.ImageWomen}
+	for _, variant := range variants {
+		sourceURLs = append(sourceURLs, variant.Image)
+	}
+	for _, template := range templates {
+		sourceURLs = append(sourceURLs, template.ImageURL, template.BackgroundURL)
+	}
+
+	return mirrorImages(ctx, sourceURLs...)
+}
+
+func mirrorCategoryImages(ctx context.Context, categories []printfulmodel.Category) error {
+	sourceURLs := make([]string, 0, len(categories))
+	for _, category := range categories {
+		sourceURLs = append(sourceURLs, category.ImageURL)
+	}
+
+	return mirrorImages(ctx, sourceURLs...)
+}
+
+// Returns a function replacing Printful urls with the url of their local copy in the images url of a store.
+// Urls not mirrored yet are left untouched
+func imageURLRewriter(storeID string) func(string) string {
+	noop := func(sourceURL string) string { return sourceURL }
+	if !printfulConfig.MirrorImages {
+		return noop
+	}
+
+	store, err := getStore(storeID)
+	if err != nil {
+		log.Println("error in imageURLRewriter:", err)
+		return noop
+	}
+
+	return func(sourceURL string) string {
+		imageID, found, err := database.FindMirroredImage(sourceURL)
+		if err != nil {
+			log.Println("error in imageURLRewriter:", err)
+			return sourceURL
+		}
+		if !found {
+			return sourceURL
+		}
+
+		imageURL, err := url.JoinPath(store.config.ImagesURL, "/", imageID)
+		if err != nil {
+			return sourceURL
+		}
+		return imageURL
+	}
+}
+
+func rewriteProductImages(storeID string, products []printfulmodel.Product) {
+	rewrite := imageURLRewriter(storeID)
+	for i := range products {
+		products[i].Image = rewrite(products[i].Image)
+		products[i].ImageWomen = rewrite(products[i].ImageWomen)
+	}
+}
+
+func rewriteVariantImages(storeID string, variants []printfulmodel.Variant) {
+	rewrite := imageURLRewriter(storeID)
+	for i := range variants {
+		variants[i].Image = rewrite(variants[i].Image)
+	}
+}
+
+func rewriteTemplateImages(storeID string, templates []printfulmodel.MockupTemplates) {
+	rewrite := imageURLRewriter(storeID)
+	for i := range templates {
+		templates[i].ImageURL = rewrite(templates[i].ImageURL)
+		templates[i].BackgroundURL = rewrite(templates[i].BackgroundURL)
+	}
+}
+
+func rewriteCategoryImages(storeID string, categories []printfulmodel.Category) {
+	rewrite := imageURLRewriter(storeID)
+	for i := range categories {
+		categories[i].ImageURL = rewrite(categories[i].ImageURL)
+	}
+}
@@ -0,0 +1,100 @@
+package printful
+
+import (
+	"fmt"
+	"go-printful-api/src/database"
+	"log"
+	"math"
+	"slices"
+	"strconv"
+	"time"
+
+	printfulmodel "github.com/baldurstod/go-printful-sdk/model"
+)
+
+// Store the prices of every variant which changed since the previous refresh and report the variations exceeding the configured threshold
+func recordPriceHistory(previousPrices *printfulmodel.ProductPrices, prices *printfulmodel.ProductPrices) error {
+	for _, variant := range prices.Variants {
+		var previousVariant *printfulmodel.VariantsPriceData
+		if previousPrices != nil {
+			idx := slices.IndexFunc(previousPrices.Variants, func(v printfulmodel.VariantsPriceData) bool { return v.ID == variant.ID })
+			if idx != -1 {
+				previousVariant = &previousPrices.Variants[idx]
+			}
+		}
+
+		if previousVariant != nil && slices.Equal(previousVariant.Techniques, variant.Techniques) {
+			continue
+		}
+
+		if err := database.InsertPriceHistory(prices.Product.ID, prices.Currency, &variant); err != nil {
+			return fmt.Errorf("error in recordPriceHistory: %w", err)
+		}
+
+		if previousVariant != nil {
+			reportPriceChange(prices.Product.ID, prices.Currency, previousVariant, &variant)
+		}
+	}
+
+	return nil
+}
+
+func reportPriceChange(productID int, currency string, previousVariant *printfulmodel.VariantsPriceData, variant *printfulmodel.VariantsPriceData) {
+	for _, technique := range variant.Techniques {
+		idx := slices.IndexFunc(previousVariant.Techniques, func(t printfulmodel.TechniquePriceInfo) bool { return t.TechniqueKey == technique.TechniqueKey })
+		if idx == -1 {
+			continue
+		}
+
+		previousPrice := previousVariant.Techniques[idx].Price
+		variation, err := priceVariation(previousPrice, technique.Price)
+		if err != nil {
+			log.Println("unable to compare prices for variant", variant.ID, err)
+			continue
+		}
+
+		if math.Abs(variation) > printfulConfig.PriceChangeThreshold {
+			log.Printf("Price change for product %d variant %d technique %s: %s -> %s %s (%+.2f%%)\n", productID, variant.ID, technique.TechniqueKey, previousPrice, technique.Price, currency, variation)
+		}
+	}
+}
+
+// Returns the variation in percent between two prices
+func priceVariation(previousPrice string, price string) (float64, error) {
+	p1, err := strconv.ParseFloat(previousPrice, 64)
+	if err != nil {
+		return 0, err
+	}
+
+	p2, err := strconv.ParseFloat(price, 64)
+	if err != nil {
+		return 0, err
+	}
+
+	if p1 == 0 {
+		return 0, nil
+	}
+
+	return (p2 - p1) / p1 * 100, nil
+}
+
+func GetPriceHistory(productID int, variantID int, currency string) ([]database.PriceHistory, error) {
+	history, err := database.FindPriceHistory(productID, variantID, currency)
+	if err != nil {
+		return nil, fmt.Errorf("unable to find price history: <%w>", err)
+	}
+
+	return history, nil
+}
+
+// Returns the variants whose cost moved beyond the configured threshold in the last days
+func GetPriceChanges(currency string, days int) ([]database.PriceChange, error) {
+	since := time.Now().AddDate(0, 0, -days).Unix()
+
+	changes, err := database.FindPriceChanges(currency, since, printfulConfig.PriceChangeThreshold)
+	if err != nil {
+		return nil, fmt.Errorf("unable to find price changes: <%w>", err)
+	}
+
+	return changes, nil
+}
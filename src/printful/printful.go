@@ -1,6 +1,7 @@
 package printful
 
 import (
+	"database/sql"
 	"encoding/json"
 	"errors"
 	"fmt"
@@ -16,6 +17,7 @@ import (
 
 	"bytes"
 	"encoding/base64"
+	"go-printful-api/src/apierrors"
 	"go-printful-api/src/config"
 	"go-printful-api/src/database"
 	"go-printful-api/src/model"
@@ -42,6 +44,7 @@ func SetPrintfulConfig(config config.Printful) {
 	printfulConfig = config
 	log.Println(config)
 	printfulClient.SetAccessToken(config.AccessToken)
+	initStores(config)
 	//go initAllProducts()
 }
 
@@ -113,11 +116,15 @@ func fetchRateLimited(method string, apiURL string, path string, headers map[str
 		}
 
 		if resp.StatusCode != 200 { //Everything except 429 and 200
-			return nil, fmt.Errorf("printful returned HTTP status code: %d", resp.StatusCode)
+			return nil, upstreamError(resp)
 		}
 		break
 	}
 
+	if resp.StatusCode == 429 {
+		return nil, apierrors.RateLimited("printful rate limit exceeded", 60)
+	}
+
 	header := resp.Header
 	remaining := header.Get("X-RateLimit-Remaining")
 	if remaining == "" {
@@ -143,58 +150,99 @@ func fetchRateLimited(method string, apiURL string, path string, headers map[str
 	return resp, err
 }
 
-func RefreshAllProducts(currency string, useCache bool) error {
-	products, err := printfulClient.GetCatalogProducts()
-	if err != nil {
-		return errors.New("unable to get printful response")
-	}
+// Build an error from a Printful error response. Printful puts the error message either in result or in error.message
+func upstreamError(resp *http.Response) error {
+	defer resp.Body.Close()
 
-	for _, product := range products {
-		err = database.InsertProduct(product)
-		if err != nil {
-			log.Println("error in RefreshAllProducts:", err)
-		}
+	response := struct {
+		Result any `json:"result"`
+		Error  struct {
+			Message string `json:"message"`
+		} `json:"error"`
+	}{}
+	json.NewDecoder(resp.Body).Decode(&response)
+
+	message := response.Error.Message
+	if result, ok := response.Result.(string); ok && message == "" {
+		message = result
 	}
 
-	for _, product := range products {
-		if err = refreshVariants(product.ID, product.VariantCount, useCache); err != nil {
-			log.Println("Error while refreshing product variants", product.ID, err)
-		}
+	return apierrors.Upstream(fmt.Sprintf("printful returned HTTP status code: %d", resp.StatusCode), resp.StatusCode, message, nil)
+}
 
-		if err = refreshPrices(product.ID, currency, useCache); err != nil {
-			log.Println("Error while refreshing product prices", product.ID, err)
-		}
+// Build an error from an SDK error. label is only logged, clients get the Printful status and message
+func sdkError(label string, err error) error {
+	status, message, retryAfter := 0, "", 0
+	var httpError *printfulsdk.HTTPError
+	if errors.As(err, &httpError) {
+		status, message, retryAfter = httpError.StatusCode(), httpError.Message(), httpError.RetryAfter()
+	}
 
-		if err = refreshTemplates(product.ID, useCache); err != nil {
-			log.Println("Error while refreshing product templates", product.ID, err)
+	if status == http.StatusTooManyRequests {
+		if retryAfter <= 0 {
+			retryAfter = 60
 		}
+		rateLimited := apierrors.RateLimited("printful rate limit exceeded", retryAfter)
+		rateLimited.Err = fmt.Errorf("%s: %w", label, err)
+		return rateLimited
+	}
 
-		if err = refreshStyles(product.ID, useCache); err != nil {
-			log.Println("Error while refreshing product styles", product.ID, err)
-		}
+	return apierrors.Upstream("unable to get printful response", status, message, fmt.Errorf("%s: %w", label, err))
+}
 
-		if err = refreshCategories(product, useCache); err != nil {
-			log.Println("Error while refreshing product styles", product.ID, err)
-		}
+func RefreshProductTranslations(language string, currency string, useCache bool) error {
+	products, err := printfulClient.GetCatalogProducts(printfulsdk.WithLanguage(language))
+	if err != nil {
+		return sdkError("error in RefreshProductTranslations", err)
+	}
 
-		if err = refreshImages(product, useCache); err != nil {
-			log.Println("Error while refreshing product images", product.ID, err)
+	for _, product := range products {
+		err := database.InsertProductTranslation(language, &product)
+		if err != nil {
+			log.Println("error in RefreshAllProducts:", err)
 		}
 	}
 
 	return nil
 }
 
-func RefreshProductTranslations(language string, currency string, useCache bool) error {
-	products, err := printfulClient.GetCatalogProducts(printfulsdk.WithLanguage(language))
+func RefreshVariantTranslations(language string, useCache bool) error {
+	products, err := database.FindProducts()
 	if err != nil {
-		return errors.New("unable to get printful response")
+		return fmt.Errorf("error in RefreshVariantTranslations: %w", err)
 	}
 
 	for _, product := range products {
-		err := database.InsertProductTranslation(language, &product)
+		if err = refreshVariantTranslations(product.ID, language, useCache); err != nil {
+			log.Println("Error while refreshing variant translations", product.ID, language, err)
+		}
+	}
+
+	return nil
+}
+
+func refreshVariantTranslations(productID int, language string, useCache bool) error {
+	outdated := true
+	var err error
+
+	if useCache {
+		var translations []database.VariantTranslation
+		translations, outdated, err = database.FindVariantTranslations(productID, language)
+		if err != nil || len(translations) == 0 {
+			outdated = true
+		}
+	}
+
+	if outdated {
+		variants, err := printfulClient.GetCatalogVariants(productID, printfulsdk.WithLanguage(language))
 		if err != nil {
-			log.Println("error in RefreshAllProducts:", err)
+			return sdkError("error in refreshVariantTranslations", err)
+		}
+
+		for _, variant := range variants {
+			if err = database.InsertVariantTranslation(language, &variant); err != nil {
+				return fmt.Errorf("error in refreshVariantTranslations: %w", err)
+			}
 		}
 	}
 
@@ -220,11 +268,15 @@ func refreshVariants(productID int, count int, useCache bool) error {
 
 	if outdated {
 		log.Println("Variants for product", productID, "are outdated, refreshing")
-		variants, err = printfulClient.GetCatalogVariants(productID, printfulsdk.WithLanguage("fr_FR"))
+		variants, err = printfulClient.GetCatalogVariants(productID)
 		if err != nil {
 			//log.Println("Error while getting product variants", productID, err)
-			return fmt.Errorf("error in refreshVariants: %w", err)
+			return sdkError("error in refreshVariants", err)
 		} else {
+			previousVariantIDs, err := database.FindProductVariantIds(productID)
+			if err != nil {
+				log.Println("error in refreshVariants:", err)
+			}
 
 			variantIDs := make([]int, 0, 20)
 
@@ -235,6 +287,10 @@ func refreshVariants(productID int, count int, useCache bool) error {
 				}
 			}
 
+			if err = recordVariantChanges(productID, previousVariantIDs, variantIDs); err != nil {
+				log.Println("error in refreshVariants:", err)
+			}
+
 			if err = database.UpdateProductVariantIds(productID, variantIDs); err != nil {
 				return fmt.Errorf("error in refreshVariants: %w", err)
 			}
@@ -245,26 +301,30 @@ func refreshVariants(productID int, count int, useCache bool) error {
 
 func refreshPrices(productID int, currency string, useCache bool) error {
 	var prices *printfulmodel.ProductPrices
+	var previousPrices *printfulmodel.ProductPrices
 	outdated := true
 	var err error
 
-	if useCache {
-		_, outdated, err = database.FindProductPrices(productID, currency)
-		if err != nil {
-			outdated = true
-		}
+	// Previous prices are always fetched to be compared with the new ones
+	previousPrices, outdated, err = database.FindProductPrices(productID, currency)
+	if err != nil || !useCache {
+		outdated = true
 	}
 
 	if outdated {
 		log.Println("Prices for product", productID, "currency", currency, "are outdated, refreshing")
 		prices, err = printfulClient.GetProductPrices(productID, printfulsdk.WithCurrency(currency))
 		if err != nil {
-			return fmt.Errorf("error in refreshPrices: %w", err)
+			return sdkError("error in refreshPrices", err)
 		} else {
 			err = database.InsertProductPrices(prices)
 			if err != nil {
 				log.Println("error while refreshPrices:", err)
 			}
+
+			if err = recordPriceHistory(previousPrices, prices); err != nil {
+				log.Println("error while refreshPrices:", err)
+			}
 		}
 	}
 
@@ -287,7 +347,7 @@ func refreshTemplates(productID int, useCache bool) error {
 		log.Println("Templates for product", productID, "are outdated, refreshing")
 		templates, err = printfulClient.GetMockupTemplates(productID)
 		if err != nil {
-			return fmt.Errorf("error in refreshTemplates: %w", err)
+			return sdkError("error in refreshTemplates", err)
 		} else {
 			database.InsertMockupTemplates(productID, templates)
 		}
@@ -312,7 +372,7 @@ func refreshStyles(productID int, useCache bool) error {
 		log.Println("Styles for product", productID, "are outdated, refreshing")
 		styles, err = printfulClient.GetMockupStyles(productID)
 		if err != nil {
-			return fmt.Errorf("error in refreshStyles: %w", err)
+			return sdkError("error in refreshStyles", err)
 		} else {
 			database.InsertMockupStyles(productID, styles)
 		}
@@ -321,55 +381,10 @@ func refreshStyles(productID int, useCache bool) error {
 	return nil
 }
 
-// Look for women pictures for unisex products
-func refreshImages(product printfulmodel.Product, useCache bool) error {
-	productImages, err := printfulClient.GetProductImages(product.ID)
-	if err != nil {
-		return err
-	}
-
-	styles, _, err := database.FindMockupStyles(product.ID)
-	if err != nil {
-		return err
-	}
-
-	womenUrl := ""
-StyleLoop:
-	// Iterate the styles to find a women picture
-	for _, style := range styles {
-		for _, mockupStyle := range style.MockupStyles {
-			// Filter categories starting with "Women's" but not "Women's Lifestyle"
-			if strings.HasPrefix(mockupStyle.CategoryName, "Women's") && !strings.HasPrefix(mockupStyle.CategoryName, "Women's Lifestyle") && mockupStyle.ViewName == "Front" {
-				// Once we found a suitable style, check for pictures
-				for _, productImage := range productImages {
-					// Preferentially look for pictures of white color. If we don't find any, other colors will do
-					if productImage.Color != "White" && womenUrl != "" {
-						continue
-					}
-					for _, image := range productImage.Images {
-						if image.MockupStyleId == mockupStyle.Id {
-							womenUrl = image.ImageUrl
-							break StyleLoop
-						}
-					}
-				}
-			}
-		}
-	}
-
-	// Update the product if we found a picture
-	if womenUrl != "" {
-		product.ImageWomen = womenUrl
-		database.UpdateProduct(product, database.UpdateProductFields{ImageWomen: true})
-	}
-
-	return nil
-}
-
 func refreshCategories(product printfulmodel.Product, useCache bool) error {
 	categories, err := printfulClient.GetProductCategories(product.ID)
 	if err != nil {
-		return err
+		return sdkError("error in refreshCategories", err)
 	} else {
 		product.Categories = make([]int, 0, len(categories))
 		for _, category := range categories {
@@ -380,16 +395,60 @@ func refreshCategories(product printfulmodel.Product, useCache bool) error {
 	return nil
 }
 
-func GetCategories() ([]printfulmodel.Category, error) {
-	categories, err := database.FindCategories()
+type CategoryNode struct {
+	printfulmodel.Category
+	Children []*CategoryNode `json:"children"`
+}
+
+// Returns the categories in the requested language, falling back to en_US when a translation is missing
+func GetCategories(storeID string, language string) ([]printfulmodel.Category, error) {
+	categories, err := database.FindCategories(language)
 
 	if err != nil {
 		return nil, err
 	}
 
+	if language == "en_US" {
+		rewriteCategoryImages(storeID, categories)
+		return categories, nil
+	}
+
+	fallback, err := database.FindCategories("en_US")
+	if err != nil {
+		return nil, err
+	}
+
+	for _, category := range fallback {
+		if !slices.ContainsFunc(categories, func(c printfulmodel.Category) bool { return c.ID == category.ID }) {
+			categories = append(categories, category)
+		}
+	}
+
+	rewriteCategoryImages(storeID, categories)
+
 	return categories, nil
 }
 
+// Build a category tree using parent ids. Categories whose parent is unknown are returned as roots
+func BuildCategoryTree(categories []printfulmodel.Category) []*CategoryNode {
+	nodes := make(map[int]*CategoryNode, len(categories))
+	for _, category := range categories {
+		nodes[category.ID] = &CategoryNode{Category: category, Children: make([]*CategoryNode, 0)}
+	}
+
+	roots := make([]*CategoryNode, 0)
+	for _, category := range categories {
+		node := nodes[category.ID]
+		if parent, found := nodes[category.ParentID]; found && category.ParentID != category.ID {
+			parent.Children = append(parent.Children, node)
+		} else {
+			roots = append(roots, node)
+		}
+	}
+
+	return roots
+}
+
 type GetCountriesResponse struct {
 	Code   int                        `json:"code"`
 	Result []printfulAPIModel.Country `json:"result"`
@@ -413,28 +472,85 @@ type GetProductsResponse struct {
 //var cachedProducts = make([]printfulmodel.Product, 0)
 //var cachedProductsUpdated = time.Time{}
 
-func GetProducts(language string) ([]printfulmodel.Product, error) {
+func GetProducts(storeID string, language string) ([]printfulmodel.Product, error) {
 	products, err := database.FindProducts()
 
 	if err != nil {
 		return nil, err
 	}
 
+	if err = localizeProducts(products, language); err != nil {
+		return nil, err
+	}
+
+	rewriteProductImages(storeID, products)
+
 	return products, nil
 }
 
+// Replace product names and descriptions with their translation, falling back to en_US when a translation is missing
+func localizeProducts(products []printfulmodel.Product, language string) error {
+	translations, err := findProductTranslations(language)
+	if err != nil {
+		return err
+	}
+
+	fallback := translations
+	if language != "en_US" {
+		if fallback, err = findProductTranslations("en_US"); err != nil {
+			return err
+		}
+	}
+
+	for i := range products {
+		product := &products[i]
+
+		translation, found := translations[product.ID]
+		if !found {
+			translation, found = fallback[product.ID]
+		}
+
+		if found {
+			product.Name = translation.Name
+			product.Description = translation.Description
+		}
+	}
+
+	return nil
+}
+
+func findProductTranslations(language string) (map[int]database.ProductTranslation, error) {
+	translations, err := database.FindProductTranslations(language)
+	if err != nil {
+		return nil, fmt.Errorf("unable to find translations: <%w>", err)
+	}
+
+	translationsByID := make(map[int]database.ProductTranslation, len(translations))
+	for _, translation := range translations {
+		translationsByID[translation.ID] = translation
+	}
+
+	return translationsByID, nil
+}
+
 type GetProductResponse struct {
 	Code   int                          `json:"code"`
 	Result printfulAPIModel.ProductInfo `json:"result"`
 }
 
-func GetProduct(productID int) (*printfulmodel.Product, error) {
-	product, _, err := database.FindProduct(productID)
+func GetProduct(storeID string, productID int) (*printfulmodel.Product, error) {
+	product, err := readThrough(productID, productKey(productID),
+		func() (*printfulmodel.Product, bool, error) { return database.FindProduct(productID) },
+		func() error { return refreshProductRow(productID) },
+	)
 	if err == nil {
+		rewrite := imageURLRewriter(storeID)
+		product.Image = rewrite(product.Image)
+		product.ImageWomen = rewrite(product.ImageWomen)
 		return product, nil
 	}
 
-	return nil, errors.New("unable to find product")
+	return nil, apierrors.Wrap("unable to find product", err)
 }
 
 func GetProductTranslation(productID int, language string) (*database.ProductTranslation, error) {
@@ -445,21 +561,50 @@ func GetProductTranslation(productID int, language string) (*database.ProductTra
 	return translation, nil
 }
 
-func GetProductPrices(productID int, currency string) (*printfulmodel.ProductPrices, error) {
-	productPrices, _, err := database.FindProductPrices(productID, currency)
+// Returns a product with its name and description in the requested language, falling back to en_US when a translation is missing
+func GetLocalizedProduct(storeID string, productID int, language string) (*printfulmodel.Product, error) {
+	product, err := GetProduct(storeID, productID)
 	if err != nil {
-		return nil, errors.New("unable to find product prices")
+		return nil, err
+	}
+
+	for _, l := range []string{language, "en_US"} {
+		if translation, err := database.FindProductTranslation(productID, l); err == nil {
+			product.Name = translation.Name
+			product.Description = translation.Description
+			break
+		}
+	}
+
+	return product, nil
+}
+
+// Prices include the markup of the store
+func GetProductPrices(storeID string, productID int, currency string) (*printfulmodel.ProductPrices, error) {
+	store, err := getStore(storeID)
+	if err != nil {
+		return nil, err
+	}
+
+	productPrices, err := readThrough(productID, pricesKey(productID, currency),
+		func() (*printfulmodel.ProductPrices, bool, error) {
+			return database.FindProductPrices(productID, currency)
+		},
+		func() error { return refreshPrices(productID, currency, false) },
+	)
+	if err != nil {
+		return nil, apierrors.Wrap("unable to find product prices", err)
 	}
 
 	for i := range productPrices.Product.Placements {
 		placement := &productPrices.Product.Placements[i]
 
-		placement.Price, err = applyMarkup(placement.Price, printfulConfig.Markup) //(1 + printfulConfig.Markup/100)
+		placement.Price, err = applyMarkup(placement.Price, store.config.Markup) //(1 + store.config.Markup/100)
 		if err != nil {
 			return nil, errors.New("failed to format product price")
 		}
 
-		placement.DiscountedPrice, err = applyMarkup(placement.DiscountedPrice, printfulConfig.Markup) //(1 + printfulConfig.Markup/100)
+		placement.DiscountedPrice, err = applyMarkup(placement.DiscountedPrice, store.config.Markup) //(1 + store.config.Markup/100)
 		if err != nil {
 			return nil, errors.New("failed to format product price")
 		}
@@ -469,12 +614,12 @@ func GetProductPrices(productID int, currency string) (*printfulmodel.ProductPri
 		variant := &productPrices.Variants[i]
 		for j := range variant.Techniques {
 			technique := &variant.Techniques[j]
-			technique.Price, err = applyMarkup(technique.Price, printfulConfig.Markup) //(1 + printfulConfig.Markup/100)
+			technique.Price, err = applyMarkup(technique.Price, store.config.Markup) //(1 + store.config.Markup/100)
 			if err != nil {
 				return nil, errors.New("failed to format product price")
 			}
 
-			technique.DiscountedPrice, err = applyMarkup(technique.DiscountedPrice, printfulConfig.Markup) //(1 + printfulConfig.Markup/100)
+			technique.DiscountedPrice, err = applyMarkup(technique.DiscountedPrice, store.config.Markup) //(1 + store.config.Markup/100)
 			if err != nil {
 				return nil, errors.New("failed to format product price")
 			}
@@ -494,13 +639,55 @@ func applyMarkup(price string, pct float64) (string, error) {
 	return strconv.FormatFloat(p, 'f', 2, 64), nil
 }
 
-func GetVariants(productID int) ([]printfulmodel.Variant, error) {
-	variants, _, err := database.FindVariants(productID)
-	if err == nil {
-		return variants, nil
+func GetVariants(storeID string, productID int, language string) ([]printfulmodel.Variant, error) {
+	variants, err := readThrough(productID, variantsKey(productID),
+		func() ([]printfulmodel.Variant, bool, error) {
+			variants, outdated, err := database.FindVariants(productID)
+			if err == nil && len(variants) == 0 {
+				return nil, false, sql.ErrNoRows
+			}
+			return variants, outdated, err
+		},
+		func() error { return refreshVariants(productID, 0, false) },
+	)
+	if err != nil {
+		return nil, apierrors.Wrap("unable to find variants", err)
+	}
+
+	if err = localizeVariants(productID, variants, language); err != nil {
+		log.Println("error in GetVariants:", err)
 	}
 
-	return nil, errors.New("unable to find variants")
+	rewriteVariantImages(storeID, variants)
+
+	return variants, nil
+}
+
+// Replace variant names, colors and sizes with their translation. Variants without translation are left untouched
+func localizeVariants(productID int, variants []printfulmodel.Variant, language string) error {
+	translations, outdated, err := database.FindVariantTranslations(productID, language)
+	if err != nil {
+		return fmt.Errorf("unable to find variant translations: <%w>", err)
+	}
+
+	if outdated || len(translations) == 0 {
+		startResourceRefresh(variantTranslationsKey(productID, language), func() error { return refreshVariantTranslations(productID, language, false) })
+	}
+
+	for i := range variants {
+		idx := slices.IndexFunc(translations, func(t database.VariantTranslation) bool { return t.ID == variants[i].ID })
+		if idx != -1 {
+			localizeVariant(&variants[i], &translations[idx])
+		}
+	}
+
+	return nil
+}
+
+func localizeVariant(variant *printfulmodel.Variant, translation *database.VariantTranslation) {
+	variant.Name = translation.Name
+	variant.Color = translation.Color
+	variant.Size = translation.Size
 }
 
 type GetVariantResponse struct {
@@ -508,13 +695,22 @@ type GetVariantResponse struct {
 	Result printfulAPIModel.VariantInfo `json:"result"`
 }
 
-func GetVariant(variantID int) (*printfulmodel.Variant, error) {
-	variant, _, err := database.FindVariant(variantID)
+func GetVariant(storeID string, variantID int, language string) (*printfulmodel.Variant, error) {
+	// Missing variants can't be fetched without knowing their product
+	variant, outdated, err := database.FindVariant(variantID)
 	if err == nil {
+		if outdated {
+			startResourceRefresh(variantsKey(variant.CatalogProductID), func() error { return refreshVariants(variant.CatalogProductID, 0, false) })
+		}
+
+		if translation, err := database.FindVariantTranslation(variantID, language); err == nil {
+			localizeVariant(variant, translation)
+		}
+		variant.Image = imageURLRewriter(storeID)(variant.Image)
 		return variant, nil
 	}
 
-	return nil, errors.New("unable to find variant")
+	return nil, apierrors.Wrap("unable to find variant", err)
 }
 
 type GetTemplatesResponse struct {
@@ -522,18 +718,26 @@ type GetTemplatesResponse struct {
 	Result printfulAPIModel.ProductTemplate `json:"result"`
 }
 
-func GetMockupTemplates(productID int) ([]printfulmodel.MockupTemplates, error) {
-	templates, _, err := database.FindMockupTemplates(productID)
+func GetMockupTemplates(storeID string, productID int) ([]printfulmodel.MockupTemplates, error) {
+	templates, err := readThrough(productID, templatesKey(productID),
+		func() ([]printfulmodel.MockupTemplates, bool, error) { return database.FindMockupTemplates(productID) },
+		func() error { return refreshTemplates(productID, false) },
+	)
 
 	if err != nil {
 		return nil, err
 	}
 
+	rewriteTemplateImages(storeID, templates)
+
 	return templates, nil
 }
 
 func GetMockupStyles(productID int) ([]printfulmodel.MockupStyles, error) {
-	styles, _, err := database.FindMockupStyles(productID)
+	styles, err := readThrough(productID, stylesKey(productID),
+		func() ([]printfulmodel.MockupStyles, bool, error) { return database.FindMockupStyles(productID) },
+		func() error { return refreshStyles(productID, false) },
+	)
 
 	if err != nil {
 		return nil, err
@@ -550,20 +754,20 @@ type GetSimilarVariantsPlacement struct {
 
 func GetSimilarVariants(variantID int, placements []GetSimilarVariantsPlacement) ([]int, error) {
 	if placements == nil {
-		return nil, errors.New("placement is nil")
+		return nil, apierrors.Validation("placement is nil", nil)
 	}
 
-	variant, err := GetVariant(variantID)
+	variant, err := GetVariant(DefaultStoreID, variantID, "en_US")
 	if err != nil {
 		return nil, err
 	}
 
-	product, err := GetProduct(variant.CatalogProductID)
+	product, err := GetProduct(DefaultStoreID, variant.CatalogProductID)
 	if err != nil {
 		return nil, err
 	}
 
-	templates, err := GetMockupTemplates(variant.CatalogProductID)
+	templates, err := GetMockupTemplates(DefaultStoreID, variant.CatalogProductID)
 
 	variantsIDs := make(map[int]int, 0)
 
@@ -636,8 +840,12 @@ type CreateSyncProductResponse struct {
 	Result schemas.SyncProduct `json:"result"`
 }
 
-func CreateSyncProduct(datas model.CreateSyncProductDatas) (*schemas.SyncProduct, error) {
+func CreateSyncProduct(storeID string, datas model.CreateSyncProductDatas) (*schemas.SyncProduct, error) {
 	//log.Println("CreateSyncProduct", datas)
+	store, err := getStore(storeID)
+	if err != nil {
+		return nil, err
+	}
 
 	b64data := datas.Image[strings.IndexByte(datas.Image, ',')+1:] // Remove data:image/png;base64,
 
@@ -645,16 +853,16 @@ func CreateSyncProduct(datas model.CreateSyncProductDatas) (*schemas.SyncProduct
 
 	config, err := png.DecodeConfig(reader)
 	if err != nil {
-		return nil, err
+		return nil, apierrors.Validation("Error while decoding image", err)
 	}
 
 	if config.Width > 20000 || config.Height > 20000 {
-		return nil, errors.New("image too large")
+		return nil, apierrors.Validation("image too large", nil)
 	}
 
 	img, err := png.Decode(base64.NewDecoder(base64.StdEncoding, strings.NewReader(b64data)))
 	if err != nil {
-		return nil, err
+		return nil, apierrors.Validation("Error while decoding image", err)
 	}
 
 	newWidth, newHeight := 200, 200
@@ -702,10 +910,10 @@ func CreateSyncProduct(datas model.CreateSyncProductDatas) (*schemas.SyncProduct
 	}
 
 	headers := map[string]string{
-		"Authorization": "Bearer " + printfulConfig.AccessToken,
+		"Authorization": "Bearer " + store.config.AccessToken,
 	}
 
-	imageURL, err := url.JoinPath(printfulConfig.ImagesURL, "/", filename)
+	imageURL, err := url.JoinPath(store.config.ImagesURL, "/", filename)
 	if err != nil {
 		return nil, errors.New("unable to create image url")
 	}
@@ -725,7 +933,7 @@ func CreateSyncProduct(datas model.CreateSyncProductDatas) (*schemas.SyncProduct
 		syncVariants = append(syncVariants, syncVariant)
 	}
 
-	thumbnailURL, err := url.JoinPath(printfulConfig.ImagesURL, "/", filename+"_thumb")
+	thumbnailURL, err := url.JoinPath(store.config.ImagesURL, "/", filename+"_thumb")
 	if err != nil {
 		return nil, errors.New("unable to create thumbnail url")
 	}
@@ -742,14 +950,14 @@ func CreateSyncProduct(datas model.CreateSyncProductDatas) (*schemas.SyncProduct
 
 	resp, err := fetchRateLimited("POST", PRINTFUL_STORE_API, "/products", headers, body)
 	if err != nil {
-		return nil, errors.New("unable to get printful response")
+		return nil, apierrors.Wrap("unable to get printful response", err)
 	}
 
 	response := CreateSyncProductResponse{}
 	err = json.NewDecoder(resp.Body).Decode(&response)
 	if err != nil {
 		log.Println(err)
-		return nil, errors.New("unable to decode printful response")
+		return nil, apierrors.Upstream("unable to decode printful response", 0, "", err)
 	}
 
 	log.Println(response)
@@ -764,14 +972,19 @@ type GetSyncProductResponse struct {
 	Result printfulAPIModel.SyncProductInfo `json:"result"`
 }
 
-func GetSyncProduct(syncProductID int64) (*printfulAPIModel.SyncProductInfo, error) {
+func GetSyncProduct(storeID string, syncProductID int64) (*printfulAPIModel.SyncProductInfo, error) {
+	store, err := getStore(storeID)
+	if err != nil {
+		return nil, err
+	}
+
 	headers := map[string]string{
-		"Authorization": "Bearer " + printfulConfig.AccessToken,
+		"Authorization": "Bearer " + store.config.AccessToken,
 	}
 
 	resp, err := fetchRateLimited("GET", PRINTFUL_STORE_API, "/products/"+strconv.FormatInt(syncProductID, 10), headers, nil)
 	if err != nil {
-		return nil, errors.New("unable to get printful response")
+		return nil, apierrors.Wrap("unable to get printful response", err)
 	}
 
 	//body, _ := ioutil.ReadAll(resp.Body)
@@ -781,12 +994,12 @@ func GetSyncProduct(syncProductID int64) (*printfulAPIModel.SyncProductInfo, err
 	err = json.NewDecoder(resp.Body).Decode(&response)
 	if err != nil {
 		log.Println(err)
-		return nil, errors.New("unable to decode printful response")
+		return nil, apierrors.Upstream("unable to decode printful response", 0, "", err)
 	}
 
 	if response.Code != 200 {
 		log.Println(err)
-		return nil, errors.New("printful returned an error")
+		return nil, apierrors.Upstream("printful returned an error", response.Code, "", nil)
 	}
 
 	p := &(response.Result)
@@ -795,18 +1008,28 @@ func GetSyncProduct(syncProductID int64) (*printfulAPIModel.SyncProductInfo, err
 	return p, nil
 }
 
-func CalculateShippingRates(datas requests.CalculateShippingRates) ([]printfulmodel.ShippingRate, error) {
-	shippingRates, err := printfulClient.CalculateShippingRates(datas.Recipient, datas.Items, printfulsdk.WithCurrency(datas.Currency), printfulsdk.WithLanguage(datas.Locale))
+func CalculateShippingRates(storeID string, datas requests.CalculateShippingRates) ([]printfulmodel.ShippingRate, error) {
+	store, err := getStore(storeID)
 	if err != nil {
-		return nil, errors.New("unable to get printful response")
+		return nil, err
+	}
+
+	shippingRates, err := store.client.CalculateShippingRates(datas.Recipient, datas.Items, printfulsdk.WithCurrency(datas.Currency), printfulsdk.WithLanguage(datas.Locale))
+	if err != nil {
+		return nil, sdkError("error in CalculateShippingRates", err)
 	}
 
 	return shippingRates, nil
 }
 
-func CalculateTaxRate(datas requests.CalculateTaxRate) (*schemas.TaxInfo, error) {
+func CalculateTaxRate(storeID string, datas requests.CalculateTaxRate) (*schemas.TaxInfo, error) {
+	store, err := getStore(storeID)
+	if err != nil {
+		return nil, err
+	}
+
 	body := map[string]interface{}{}
-	err := mapstructure.Decode(datas, &body)
+	err = mapstructure.Decode(datas, &body)
 	if err != nil {
 		log.Println(err)
 		return nil, errors.New("error while decoding params")
@@ -815,12 +1038,12 @@ func CalculateTaxRate(datas requests.CalculateTaxRate) (*schemas.TaxInfo, error)
 	log.Println(body)
 
 	headers := map[string]string{
-		"Authorization": "Bearer " + printfulConfig.AccessToken,
+		"Authorization": "Bearer " + store.config.AccessToken,
 	}
 
 	resp, err := fetchRateLimited("POST", PRINTFUL_TAX_API, "/rates", headers, body)
 	if err != nil {
-		return nil, errors.New("unable to get printful response")
+		return nil, apierrors.Wrap("unable to get printful response", err)
 	}
 	defer resp.Body.Close()
 
@@ -829,7 +1052,7 @@ func CalculateTaxRate(datas requests.CalculateTaxRate) (*schemas.TaxInfo, error)
 	err = json.NewDecoder(resp.Body).Decode(&response)
 	if err != nil {
 		log.Println(err)
-		return nil, errors.New("unable to decode printful response")
+		return nil, apierrors.Upstream("unable to decode printful response", 0, "", err)
 	}
 	log.Println(response)
 
@@ -843,7 +1066,12 @@ type CreateOrderResponse struct {
 	Result schemas.Order `json:"result"`
 }
 
-func CreateOrder(request requests.CreateOrder) (*printfulmodel.Order, error) {
+func CreateOrder(storeID string, request requests.CreateOrder) (*printfulmodel.Order, error) {
+	store, err := getStore(storeID)
+	if err != nil {
+		return nil, err
+	}
+
 	opts := make([]printfulsdk.RequestOption, 0, 5)
 
 	if request.ExternalID != "" {
@@ -872,9 +1100,9 @@ func CreateOrder(request requests.CreateOrder) (*printfulmodel.Order, error) {
 		RetailCosts   model.RetailCosts2  `json:"retail_costs" bson:"retail_costs" mapstructure:"retail_costs"`
 	*/
 
-	order, err := printfulClient.CreateOrder(request.Recipient, request.OrderItems, opts...)
+	order, err := store.client.CreateOrder(request.Recipient, request.OrderItems, opts...)
 	if err != nil {
-		return nil, errors.New("unable to get printful response")
+		return nil, sdkError("error in CreateOrder", err)
 	}
 
 	return order, nil
@@ -1,13 +1,548 @@
 package printful
 
 import (
+	"context"
+	"errors"
 	"fmt"
+	"go-printful-api/src/apierrors"
 	"go-printful-api/src/database"
 	"log"
+	"slices"
+	"strconv"
+	"sync"
+	"time"
 
 	printfulsdk "github.com/baldurstod/go-printful-sdk"
+	printfulmodel "github.com/baldurstod/go-printful-sdk/model"
 )
 
+const defaultRefreshWorkers = 4
+
+// Refresh steps of a product, in execution order
+var refreshSteps = []string{"variants", "prices", "templates", "styles", "categories", "images", "mirror", "variant_translations"}
+
+type RefreshProgress struct {
+	RunID     int   `json:"run_id"`
+	Running   bool  `json:"running"`
+	Total     int   `json:"total"`
+	Done      int   `json:"done"`
+	Failed    int   `json:"failed"`
+	Remaining int   `json:"remaining"`
+	StartedAt int64 `json:"started_at"`
+	EndedAt   int64 `json:"ended_at"`
+}
+
+type RefreshStatus struct {
+	Run         *database.RefreshRun         `json:"run"`
+	Progress    *RefreshProgress             `json:"progress,omitempty"`
+	FailedSteps []database.RefreshStepResult `json:"failed_steps"`
+}
+
+var refreshProgress RefreshProgress
+var refreshProgressMutex sync.Mutex
+
+// Cancels the running refresh, guarded by refreshProgressMutex
+var refreshCancel context.CancelFunc
+
+func GetRefreshProgress() RefreshProgress {
+	refreshProgressMutex.Lock()
+	defer refreshProgressMutex.Unlock()
+
+	return refreshProgress
+}
+
+func startRefreshProgress() bool {
+	refreshProgressMutex.Lock()
+	defer refreshProgressMutex.Unlock()
+
+	if refreshProgress.Running {
+		return false
+	}
+
+	refreshProgress = RefreshProgress{Running: true, StartedAt: time.Now().Unix()}
+	return true
+}
+
+func setRefreshTotal(runID int, total int) {
+	refreshProgressMutex.Lock()
+	defer refreshProgressMutex.Unlock()
+
+	refreshProgress.RunID = runID
+	refreshProgress.Total = total
+	refreshProgress.Remaining = total
+}
+
+func updateRefreshProgress(failed bool) {
+	refreshProgressMutex.Lock()
+	defer refreshProgressMutex.Unlock()
+
+	if failed {
+		refreshProgress.Failed++
+	} else {
+		refreshProgress.Done++
+	}
+	refreshProgress.Remaining--
+}
+
+func endRefreshProgress() {
+	refreshProgressMutex.Lock()
+	defer refreshProgressMutex.Unlock()
+
+	refreshProgress.Running = false
+	refreshProgress.EndedAt = time.Now().Unix()
+	refreshCancel = nil
+}
+
+func setRefreshCancel(cancel context.CancelFunc) {
+	refreshProgressMutex.Lock()
+	defer refreshProgressMutex.Unlock()
+
+	refreshCancel = cancel
+}
+
+// Cancel the running refresh or retry. Steps in progress are completed. Returns false if no refresh can be cancelled
+func CancelRefresh() bool {
+	refreshProgressMutex.Lock()
+	defer refreshProgressMutex.Unlock()
+
+	if refreshCancel == nil {
+		return false
+	}
+
+	refreshCancel()
+	return true
+}
+
+// Refresh the whole catalog using a pool of workers. Printful requests are throttled by the client rate limiter,
+// the number of workers only bounds how many products are refreshed at the same time.
+// Cancelling ctx stops the refresh once the steps in progress are done.
+// The outcome of every step is stored in a refresh run, see GetRefreshStatus
+func RefreshAllProducts(ctx context.Context, currency string, useCache bool) error {
+	if !startRefreshProgress() {
+		return errors.New("a refresh is already running")
+	}
+	defer endRefreshProgress()
+
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+	setRefreshCancel(cancel)
+
+	run := database.RefreshRun{
+		Currency:    currency,
+		UseCache:    useCache,
+		Status:      database.RefreshRunning,
+		DateStarted: time.Now().Unix(),
+	}
+
+	var err error
+	if run.ID, err = database.InsertRefreshRun(&run); err != nil {
+		log.Println("error in RefreshAllProducts:", err)
+	}
+
+	products, err := printfulClient.GetCatalogProducts()
+	if err != nil {
+		return finishRefreshRun(&run, sdkError("error in RefreshAllProducts", err))
+	}
+
+	if err = recordProductChanges(products); err != nil {
+		log.Println("error in RefreshAllProducts:", err)
+	}
+
+	for _, product := range products {
+		err = database.InsertProduct(product)
+		if err != nil {
+			log.Println("error in RefreshAllProducts:", err)
+		}
+	}
+
+	setRefreshTotal(run.ID, len(products))
+
+	if run.ID != 0 {
+		productIDs := make([]int, 0, len(products))
+		for _, product := range products {
+			productIDs = append(productIDs, product.ID)
+		}
+		if err = database.InsertPendingRefreshSteps(run.ID, productIDs, refreshSteps); err != nil {
+			log.Println("error in RefreshAllProducts:", err)
+		}
+	}
+
+	runRefreshPool(ctx, products, func(product printfulmodel.Product) error {
+		return refreshProductDetails(ctx, run.ID, product, currency, useCache, refreshSteps)
+	})
+
+	return finishRefreshRun(&run, ctx.Err())
+}
+
+type refreshRetry struct {
+	run         *database.RefreshRun
+	failedSteps map[int][]string
+	productIDs  []int
+}
+
+// Refresh again the steps which failed or did not run during a refresh run. If runID is 0, the latest run is used
+func RetryFailedRefresh(ctx context.Context, runID int) error {
+	retry, err := prepareRetry(runID)
+	if err != nil {
+		return err
+	}
+
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+	setRefreshCancel(cancel)
+
+	return runRetry(ctx, retry)
+}
+
+// Same as RetryFailedRefresh, but returns once the retry is started. Errors preventing the retry to start are returned.
+// The retry is not bound to the caller, it runs until it completes or CancelRefresh is called
+func StartRetryFailedRefresh(runID int) error {
+	retry, err := prepareRetry(runID)
+	if err != nil {
+		return err
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	setRefreshCancel(cancel)
+
+	go func() {
+		defer cancel()
+		if err := runRetry(ctx, retry); err != nil {
+			log.Println("error while retrying refresh:", err)
+		}
+	}()
+
+	return nil
+}
+
+// Takes the refresh lock. The lock is released on error, otherwise by runRetry
+func prepareRetry(runID int) (*refreshRetry, error) {
+	if !startRefreshProgress() {
+		return nil, apierrors.Validation("a refresh is already running", nil)
+	}
+
+	retry, err := findRetrySteps(runID)
+	if err != nil {
+		endRefreshProgress()
+		return nil, err
+	}
+
+	setRefreshTotal(retry.run.ID, len(retry.productIDs))
+
+	return retry, nil
+}
+
+func findRetrySteps(runID int) (*refreshRetry, error) {
+	run, err := database.FindRefreshRun(runID)
+	if err != nil {
+		return nil, apierrors.Wrap("unable to find refresh run", err)
+	}
+
+	results, err := database.FindRefreshStepResults(run.ID, true)
+	if err != nil {
+		return nil, fmt.Errorf("unable to find refresh steps: <%w>", err)
+	}
+
+	if len(results) == 0 {
+		return nil, apierrors.Validation("refresh run "+strconv.Itoa(run.ID)+" has no step to retry", nil)
+	}
+
+	retry := &refreshRetry{run: run, failedSteps: make(map[int][]string), productIDs: make([]int, 0)}
+	for _, result := range results {
+		if _, found := retry.failedSteps[result.ProductID]; !found {
+			retry.productIDs = append(retry.productIDs, result.ProductID)
+		}
+		retry.failedSteps[result.ProductID] = append(retry.failedSteps[result.ProductID], result.Step)
+	}
+
+	return retry, nil
+}
+
+func runRetry(ctx context.Context, retry *refreshRetry) error {
+	defer endRefreshProgress()
+
+	run := retry.run
+	runRefreshPool(ctx, retry.productIDs, func(productID int) error {
+		product, err := printfulClient.GetCatalogProduct(productID)
+		if err != nil {
+			log.Println("Error while retrieving product", productID, err)
+			return err
+		}
+
+		// Keep the original order of the steps
+		steps := make([]string, 0, len(refreshSteps))
+		for _, step := range refreshSteps {
+			if slices.Contains(retry.failedSteps[productID], step) {
+				steps = append(steps, step)
+			}
+		}
+
+		return refreshProductDetails(ctx, run.ID, *product, run.Currency, false, steps)
+	})
+
+	if err := countRefreshSteps(run); err != nil {
+		return err
+	}
+
+	return saveRefreshRun(run, ctx.Err())
+}
+
+// Set the counters of a run from its steps: a product is done when all its steps succeeded
+func countRefreshSteps(run *database.RefreshRun) error {
+	results, err := database.FindRefreshStepResults(run.ID, false)
+	if err != nil {
+		return fmt.Errorf("unable to find refresh steps: <%w>", err)
+	}
+
+	succeeded := make(map[int]bool)
+	for _, result := range results {
+		if done, found := succeeded[result.ProductID]; !found || done {
+			succeeded[result.ProductID] = result.Success
+		}
+	}
+
+	run.Total = len(succeeded)
+	run.Done = 0
+	run.Failed = 0
+	for _, done := range succeeded {
+		if done {
+			run.Done++
+		} else {
+			run.Failed++
+		}
+	}
+
+	return nil
+}
+
+func GetRefreshStatus(runID int) (*RefreshStatus, error) {
+	run, err := database.FindRefreshRun(runID)
+	if err != nil {
+		return nil, fmt.Errorf("unable to find refresh run: <%w>", err)
+	}
+
+	failedSteps, err := database.FindRefreshStepResults(run.ID, true)
+	if err != nil {
+		return nil, fmt.Errorf("unable to find refresh steps: <%w>", err)
+	}
+
+	status := RefreshStatus{
+		Run:         run,
+		FailedSteps: failedSteps,
+	}
+
+	if progress := GetRefreshProgress(); progress.Running && progress.RunID == run.ID {
+		status.Progress = &progress
+	}
+
+	return &status, nil
+}
+
+func finishRefreshRun(run *database.RefreshRun, err error) error {
+	progress := GetRefreshProgress()
+	run.Total = progress.Total
+	run.Done = progress.Done
+	run.Failed = progress.Failed
+
+	return saveRefreshRun(run, err)
+}
+
+func saveRefreshRun(run *database.RefreshRun, err error) error {
+	switch {
+	case errors.Is(err, context.Canceled) || errors.Is(err, context.DeadlineExceeded):
+		run.Status = database.RefreshCancelled
+	case err != nil:
+		run.Status = database.RefreshFailed
+	case run.Failed > 0:
+		run.Status = database.RefreshFailed
+		err = fmt.Errorf("%d products failed to refresh", run.Failed)
+	default:
+		run.Status = database.RefreshCompleted
+	}
+	run.DateEnded = time.Now().Unix()
+
+	if run.ID != 0 {
+		if updateErr := database.UpdateRefreshRun(run); updateErr != nil {
+			log.Println("error while saving refresh run:", updateErr)
+		}
+	}
+
+	return err
+}
+
+// Dispatch jobs to a bounded pool of workers and wait for them to complete
+func runRefreshPool[T any](ctx context.Context, jobs []T, refresh func(T) error) {
+	workers := printfulConfig.RefreshWorkers
+	if workers <= 0 {
+		workers = defaultRefreshWorkers
+	}
+
+	ch := make(chan T)
+	var wg sync.WaitGroup
+	for i := 0; i < workers; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for job := range ch {
+				err := refresh(job)
+				updateRefreshProgress(err != nil)
+			}
+		}()
+	}
+
+JobLoop:
+	for _, job := range jobs {
+		select {
+		case ch <- job:
+		case <-ctx.Done():
+			break JobLoop
+		}
+	}
+	close(ch)
+	wg.Wait()
+}
+
+// Refresh the selected steps of a product and record the outcome of each one
+func refreshProductDetails(ctx context.Context, runID int, product printfulmodel.Product, currency string, useCache bool, steps []string) error {
+	refreshers := map[string]func() error{
+		"variants":   func() error { return refreshVariants(product.ID, product.VariantCount, useCache) },
+		"prices":     func() error { return refreshPrices(product.ID, currency, useCache) },
+		"templates":  func() error { return refreshTemplates(product.ID, useCache) },
+		"styles":     func() error { return refreshStyles(product.ID, useCache) },
+		"categories": func() error { return refreshCategories(product, useCache) },
+		"images":     func() error { return refreshImages(product, useCache) },
+		"mirror":     func() error { return mirrorProductImages(ctx, product.ID) },
+		"variant_translations": func() error {
+			errs := make([]error, 0)
+			for _, language := range printfulsdk.Languages {
+				if err := refreshVariantTranslations(product.ID, language, useCache); err != nil {
+					errs = append(errs, fmt.Errorf("%s: %w", language, err))
+				}
+			}
+			return errors.Join(errs...)
+		},
+	}
+
+	errs := make([]error, 0)
+	for _, step := range steps {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+
+		result := database.RefreshStepResult{RunID: runID, ProductID: product.ID, Step: step, Success: true}
+		if err := refreshers[step](); err != nil {
+			log.Println("Error while refreshing product", step, product.ID, err)
+			errs = append(errs, fmt.Errorf("%s: %w", step, err))
+			result.Success = false
+			result.Error = err.Error()
+		}
+
+		if runID != 0 {
+			if err := database.InsertRefreshStepResult(&result); err != nil {
+				log.Println("error while saving refresh step:", err)
+			}
+		}
+	}
+
+	return errors.Join(errs...)
+}
+
+// Refresh a single product and all its related data, bypassing the cache.
+// Rejected while another refresh is running, they would write the same rows
+func RefreshProduct(productID int) error {
+	if !startRefreshProgress() {
+		return apierrors.Validation("a refresh is already running", nil)
+	}
+	defer endRefreshProgress()
+	setRefreshTotal(0, 1)
+
+	err := refreshProduct(productID)
+	updateRefreshProgress(err != nil)
+
+	return err
+}
+
+func refreshProduct(productID int) error {
+	product, err := printfulClient.GetCatalogProduct(productID)
+	if err != nil {
+		return sdkError("error in RefreshProduct while fetching product", err)
+	}
+
+	if err = recordProductChange(product); err != nil {
+		log.Println("error in RefreshProduct:", err)
+	}
+
+	if err = database.InsertProduct(*product); err != nil {
+		return fmt.Errorf("error in RefreshProduct: %w", err)
+	}
+
+	errs := make([]error, 0)
+	if err = refreshVariants(product.ID, product.VariantCount, false); err != nil {
+		errs = append(errs, err)
+	}
+
+	for _, currency := range refreshCurrencies() {
+		if err = refreshPrices(product.ID, currency, false); err != nil {
+			errs = append(errs, err)
+		}
+	}
+
+	if err = refreshTemplates(product.ID, false); err != nil {
+		errs = append(errs, err)
+	}
+
+	if err = refreshStyles(product.ID, false); err != nil {
+		errs = append(errs, err)
+	}
+
+	if err = refreshCategories(*product, false); err != nil {
+		errs = append(errs, err)
+	}
+
+	// Must run after refreshStyles
+	if err = refreshImages(*product, false); err != nil {
+		errs = append(errs, err)
+	}
+
+	// Must run after refreshVariants and refreshTemplates
+	if err = mirrorProductImages(context.Background(), product.ID); err != nil {
+		errs = append(errs, err)
+	}
+
+	for _, language := range printfulsdk.Languages {
+		if err = refreshProductTranslation(product.ID, language); err != nil {
+			errs = append(errs, err)
+		}
+
+		if err = refreshVariantTranslations(product.ID, language, false); err != nil {
+			errs = append(errs, err)
+		}
+	}
+
+	return errors.Join(errs...)
+}
+
+func refreshProductTranslation(productID int, language string) error {
+	product, err := printfulClient.GetCatalogProduct(productID, printfulsdk.WithLanguage(language))
+	if err != nil {
+		return fmt.Errorf("error in refreshProductTranslation: %w", err)
+	}
+
+	if err = database.InsertProductTranslation(language, product); err != nil {
+		return fmt.Errorf("error in refreshProductTranslation: %w", err)
+	}
+
+	return nil
+}
+
+// Currencies to refresh prices for, defaults to USD
+func refreshCurrencies() []string {
+	if len(printfulConfig.Currencies) == 0 {
+		return []string{"USD"}
+	}
+	return printfulConfig.Currencies
+}
+
 func RefreshCountries() error {
 	countries, err := printfulClient.GetCountries()
 
@@ -37,5 +572,9 @@ func RefreshCategories(language string) error {
 			log.Println("error in RefreshCategories:", err)
 		}
 	}
+
+	if err = mirrorCategoryImages(context.Background(), categories); err != nil {
+		log.Println("error in RefreshCategories:", err)
+	}
 	return nil
 }
@@ -0,0 +1,118 @@
+package printful
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"go-printful-api/src/apierrors"
+	"go-printful-api/src/database"
+	"log"
+	"strconv"
+	"sync"
+)
+
+type pendingRefresh struct {
+	done chan struct{}
+	err  error
+}
+
+var pendingRefreshes = make(map[string]*pendingRefresh)
+var pendingRefreshesMutex sync.Mutex
+
+// Start refreshing a resource unless a refresh of the same resource is already in progress
+func startResourceRefresh(key string, refresh func() error) *pendingRefresh {
+	pendingRefreshesMutex.Lock()
+	defer pendingRefreshesMutex.Unlock()
+
+	if pending, found := pendingRefreshes[key]; found {
+		return pending
+	}
+
+	pending := &pendingRefresh{done: make(chan struct{})}
+	pendingRefreshes[key] = pending
+
+	go func() {
+		pending.err = refresh()
+		if pending.err != nil {
+			log.Println("error while refreshing", key, pending.err)
+		}
+
+		pendingRefreshesMutex.Lock()
+		delete(pendingRefreshes, key)
+		pendingRefreshesMutex.Unlock()
+
+		close(pending.done)
+	}()
+
+	return pending
+}
+
+// Read a resource of a product from the database. Outdated resources are served as is and refreshed in the background,
+// missing resources are fetched from Printful and stored before being read again.
+// Only the resources of products known from the catalog are fetched, unknown ids must not cost Printful requests
+func readThrough[T any](productID int, key string, find func() (T, bool, error), refresh func() error) (T, error) {
+	value, outdated, err := find()
+
+	switch {
+	case errors.Is(err, sql.ErrNoRows):
+		known, knownErr := database.HasProduct(productID)
+		if knownErr != nil {
+			return value, knownErr
+		}
+		if !known {
+			return value, apierrors.NotFound("unknown product "+strconv.Itoa(productID), err)
+		}
+
+		pending := startResourceRefresh(key, refresh)
+		<-pending.done
+		if pending.err != nil {
+			return value, pending.err
+		}
+		value, _, err = find()
+	case err == nil && outdated:
+		startResourceRefresh(key, refresh)
+	}
+
+	return value, err
+}
+
+func refreshProductRow(productID int) error {
+	product, err := printfulClient.GetCatalogProduct(productID)
+	if err != nil {
+		return sdkError("error in refreshProductRow", err)
+	}
+
+	if err = database.InsertProduct(*product); err != nil {
+		return fmt.Errorf("error in refreshProductRow: %w", err)
+	}
+
+	return nil
+}
+
+func productKey(productID int) string {
+	return "product/" + strconv.Itoa(productID)
+}
+
+func variantsKey(productID int) string {
+	return "variants/" + strconv.Itoa(productID)
+}
+
+func variantTranslationsKey(productID int, language string) string {
+	return "variant_translations/" + strconv.Itoa(productID) + "/" + language
+}
+
+func pricesKey(productID int, currency string) string {
+	return "prices/" + strconv.Itoa(productID) + "/" + currency
+}
+
+func templatesKey(productID int) string {
+	return "templates/" + strconv.Itoa(productID)
+}
+
+func stylesKey(productID int) string {
+	return "styles/" + strconv.Itoa(productID)
+}
+
+func imagesKey(productID int) string {
+	return "images/" + strconv.Itoa(productID)
+}
@@ -0,0 +1,100 @@
+package printful
+
+import (
+	"fmt"
+	"go-printful-api/src/database"
+	"go-printful-api/src/model/requests"
+	"slices"
+
+	printfulmodel "github.com/baldurstod/go-printful-sdk/model"
+)
+
+const maxSearchLimit = 100
+
+type SearchProductsResult struct {
+	Products []printfulmodel.Product `json:"products"`
+	Total    int                     `json:"total"`
+	Offset   int                     `json:"offset"`
+	Limit    int                     `json:"limit"`
+}
+
+func SearchProducts(storeID string, request requests.SearchProductsRequest) (*SearchProductsResult, error) {
+	search := database.ProductSearch{
+		Query:        request.Query,
+		Language:     request.Language,
+		Technique:    request.Technique,
+		Placement:    request.Placement,
+		Brand:        request.Brand,
+		Color:        request.Color,
+		Size:         request.Size,
+		Discontinued: request.Discontinued,
+		Sort:         request.Sort,
+		Order:        request.Order,
+		Offset:       request.Offset,
+		Limit:        min(request.Limit, maxSearchLimit),
+	}
+
+	if search.Limit <= 0 {
+		search.Limit = 50
+	}
+
+	if len(request.Categories) > 0 {
+		categories, err := database.FindCategories("en_US")
+		if err != nil {
+			return nil, fmt.Errorf("unable to find categories: <%w>", err)
+		}
+		search.Categories = categoryDescendants(BuildCategoryTree(categories), request.Categories)
+	}
+
+	ids, total, err := database.SearchProducts(&search)
+	if err != nil {
+		return nil, fmt.Errorf("unable to search products: <%w>", err)
+	}
+
+	products, err := GetProducts(storeID, request.Language)
+	if err != nil {
+		return nil, err
+	}
+
+	result := SearchProductsResult{
+		Products: make([]printfulmodel.Product, 0, len(ids)),
+		Total:    total,
+		Offset:   search.Offset,
+		Limit:    search.Limit,
+	}
+
+	for _, id := range ids {
+		idx := slices.IndexFunc(products, func(p printfulmodel.Product) bool { return p.ID == id })
+		if idx != -1 {
+			result.Products = append(result.Products, products[idx])
+		}
+	}
+
+	return &result, nil
+}
+
+// Returns the given categories and all their descendants
+func categoryDescendants(tree []*CategoryNode, categoryIDs []int) []int {
+	descendants := make([]int, 0, len(categoryIDs))
+
+	var walk func(nodes []*CategoryNode, selected bool)
+	walk = func(nodes []*CategoryNode, selected bool) {
+		for _, node := range nodes {
+			s := selected || slices.Contains(categoryIDs, node.ID)
+			if s {
+				descendants = append(descendants, node.ID)
+			}
+			walk(node.Children, s)
+		}
+	}
+	walk(tree, false)
+
+	// Keep unknown categories, they may not be refreshed yet
+	for _, id := range categoryIDs {
+		if !slices.Contains(descendants, id) {
+			descendants = append(descendants, id)
+		}
+	}
+
+	return descendants
+}
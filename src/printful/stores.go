@@ -0,0 +1,88 @@
+package printful
+
+import (
+	"go-printful-api/src/apierrors"
+	"go-printful-api/src/config"
+	"log"
+
+	printfulsdk "github.com/baldurstod/go-printful-sdk"
+)
+
+const DefaultStoreID = "default"
+
+type store struct {
+	config config.Store
+	client *printfulsdk.PrintfulClient
+}
+
+var stores = make(map[string]*store)
+
+// The default store is built from the top level config, it uses the client refreshing the catalog.
+// Its currency is the first refreshed currency, stores without images url or currency use the ones of the default store
+func initStores(printfulConfig config.Printful) {
+	stores = make(map[string]*store)
+	defaultCurrency := refreshCurrencies()[0]
+
+	stores[DefaultStoreID] = &store{
+		config: config.Store{
+			ID:              DefaultStoreID,
+			AccessToken:     printfulConfig.AccessToken,
+			Markup:          printfulConfig.Markup,
+			ImagesURL:       printfulConfig.ImagesURL,
+			DefaultCurrency: defaultCurrency,
+		},
+		client: printfulClient,
+	}
+
+	for _, storeConfig := range printfulConfig.Stores {
+		if storeConfig.ID == "" {
+			log.Println("ignoring store without id")
+			continue
+		}
+		if _, found := stores[storeConfig.ID]; found {
+			log.Println("ignoring duplicate store", storeConfig.ID)
+			continue
+		}
+
+		if storeConfig.ImagesURL == "" {
+			storeConfig.ImagesURL = printfulConfig.ImagesURL
+		}
+		if storeConfig.DefaultCurrency == "" {
+			storeConfig.DefaultCurrency = defaultCurrency
+		}
+
+		stores[storeConfig.ID] = &store{
+			config: storeConfig,
+			client: printfulsdk.NewPrintfulClient(storeConfig.AccessToken),
+		}
+	}
+}
+
+// Returns the default store if storeID is empty
+func getStore(storeID string) (*store, error) {
+	if storeID == "" {
+		storeID = DefaultStoreID
+	}
+
+	s, found := stores[storeID]
+	if !found {
+		return nil, apierrors.Validation("unknown store "+storeID, nil)
+	}
+
+	return s, nil
+}
+
+func HasStore(storeID string) bool {
+	_, found := stores[storeID]
+	return found
+}
+
+// Returns the config of a store. Returns the default store if storeID is empty
+func GetStoreConfig(storeID string) (config.Store, error) {
+	s, err := getStore(storeID)
+	if err != nil {
+		return config.Store{}, err
+	}
+
+	return s.config, nil
+}
@@ -1,19 +1,29 @@
 package main_test
 
 import (
+	"context"
+	"database/sql"
 	"encoding/json"
+	"fmt"
+	"go-printful-api/src/api"
 	"go-printful-api/src/config"
 	"go-printful-api/src/database"
+	"go-printful-api/src/model/requests"
 	"go-printful-api/src/printful"
 	"log"
+	"net/url"
 	"os"
 	"path"
 	"runtime"
+	"slices"
 	"strconv"
+	"strings"
 	"sync"
 	"testing"
+	"time"
 
 	printfulsdk "github.com/baldurstod/go-printful-sdk"
+	_ "github.com/lib/pq"
 )
 
 func init() {
@@ -38,7 +48,7 @@ func RefreshAllProducts(currency string) {
 	wg.Add(1)
 	go func() {
 		defer wg.Done()
-		printful.RefreshAllProducts(currency, true)
+		printful.RefreshAllProducts(context.Background(), currency, true)
 	}()
 	wg.Wait()
 }
@@ -53,25 +63,75 @@ func RefreshProductTranslations(language string, currency string) {
 	wg.Wait()
 }
 
+var testConfig config.Config
+
 func initConfig() error {
 	var err error
 	var content []byte
-	config := config.Config{}
 
 	if content, err = os.ReadFile("config.json"); err != nil {
 		return err
 	}
-	if err = json.Unmarshal(content, &config); err != nil {
+	if err = json.Unmarshal(content, &testConfig); err != nil {
 		return err
 	}
-	printful.SetPrintfulConfig(config.Printful)
-	database.InitPrintfulDB(config.Databases.Printful)
-	database.InitImagesDB(config.Databases.Images)
+	printful.SetPrintfulConfig(testConfig.Printful)
+	database.InitPrintfulDB(testConfig.Databases.Printful)
+	database.InitImagesDB(testConfig.Databases.Images)
 	return nil
 }
 
+// Runs the test against an empty schema created from tables.sql, dropped when the test ends.
+// Tests writing to the database must use it to leave the configured database untouched
+func useScratchSchema(t *testing.T) {
+	datasource := testConfig.Databases.Printful.Datasource
+
+	db, err := sql.Open("postgres", datasource)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tables, err := os.ReadFile("tables.sql")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	schema := "printful_test_" + strconv.FormatInt(time.Now().UnixNano(), 36)
+	if _, err = db.Exec("CREATE SCHEMA " + schema + "; SET search_path TO " + schema + ";\n" + string(tables)); err != nil {
+		db.Close()
+		t.Fatal(err)
+	}
+
+	t.Cleanup(func() {
+		database.InitPrintfulDB(testConfig.Databases.Printful)
+		if _, err := db.Exec("DROP SCHEMA " + schema + " CASCADE"); err != nil {
+			t.Error(err)
+		}
+		db.Close()
+	})
+
+	scratch := testConfig.Databases.Printful
+	scratch.Datasource = withSearchPath(datasource, schema)
+	database.InitPrintfulDB(scratch)
+}
+
+func withSearchPath(datasource string, schema string) string {
+	if !strings.Contains(datasource, "://") {
+		return datasource + " search_path=" + schema
+	}
+
+	u, err := url.Parse(datasource)
+	if err != nil {
+		return datasource
+	}
+	query := u.Query()
+	query.Set("search_path", schema)
+	u.RawQuery = query.Encode()
+	return u.String()
+}
+
 func TestGetProducts(t *testing.T) {
-	products, err := printful.GetProducts("en_US")
+	products, err := printful.GetProducts(printful.DefaultStoreID, "en_US")
 	if err != nil {
 		t.Error(err)
 		return
@@ -88,7 +148,7 @@ func TestGetProducts(t *testing.T) {
 
 func TestGetProduct(t *testing.T) {
 	id := 823
-	products, err := printful.GetProduct(id)
+	products, err := printful.GetProduct(printful.DefaultStoreID, id)
 	if err != nil {
 		t.Error(err)
 		return
@@ -103,8 +163,30 @@ func TestGetProduct(t *testing.T) {
 	}
 }
 
+func TestGetLocalizedProduct(t *testing.T) {
+	product, err := printful.GetLocalizedProduct(printful.DefaultStoreID, 823, "fr_FR")
+	if err != nil {
+		t.Error(err)
+		return
+	}
+
+	if product.Name == "" {
+		t.Error("missing product name")
+	}
+}
+
+func TestSearchProducts(t *testing.T) {
+	result, err := printful.SearchProducts(printful.DefaultStoreID, requests.SearchProductsRequest{Query: "t-shirt", Language: "fr_FR", Technique: "dtg", Limit: 10})
+	if err != nil {
+		t.Error(err)
+		return
+	}
+
+	log.Println("search results", result.Total, len(result.Products))
+}
+
 func TestGetVariants(t *testing.T) {
-	products, err := printful.GetVariants(679)
+	products, err := printful.GetVariants(printful.DefaultStoreID, 679, "en_US")
 	if err != nil {
 		t.Error(err)
 		return
@@ -120,7 +202,7 @@ func TestGetVariants(t *testing.T) {
 }
 
 func TestTemplates(t *testing.T) {
-	variants, err := printful.GetVariants(679)
+	variants, err := printful.GetVariants(printful.DefaultStoreID, 679, "en_US")
 	if err != nil {
 		t.Error(err)
 		return
@@ -135,15 +217,87 @@ func TestTemplates(t *testing.T) {
 	}
 }
 
+func TestGetProductImages(t *testing.T) {
+	images, err := printful.GetProductImages(679, "White", 0)
+	if err != nil {
+		t.Error(err)
+		return
+	}
+
+	for _, image := range images {
+		if image.Color != "White" {
+			t.Error("unexpected color", image.Color)
+		}
+	}
+}
+
 func TestRefreshAllProducts(t *testing.T) {
 	start := time.Now()
 	RefreshAllProducts("USD")
 	for _, lang := range printfulsdk.Languages {
 		RefreshProductTranslations(lang, "USD")
+		printful.RefreshVariantTranslations(lang, true)
 	}
 	fmt.Println("took", time.Since(start).Minutes(), "min")
 }
 
+func TestGetRefreshStatus(t *testing.T) {
+	status, err := printful.GetRefreshStatus(0)
+	if err != nil {
+		t.Error(err)
+		return
+	}
+
+	run := status.Run
+	if run.Status == database.RefreshRunning || run.Status == database.RefreshCancelled {
+		log.Println("refresh run", run.ID, "is", run.Status, "counters are not final")
+		return
+	}
+
+	steps, err := database.FindRefreshStepResults(run.ID, false)
+	if err != nil {
+		t.Error(err)
+		return
+	}
+
+	// A product is done when all its steps succeeded
+	succeeded := make(map[int]bool)
+	for _, step := range steps {
+		if done, found := succeeded[step.ProductID]; !found || done {
+			succeeded[step.ProductID] = step.Success
+		}
+	}
+
+	done, failed := 0, 0
+	for _, ok := range succeeded {
+		if ok {
+			done++
+		} else {
+			failed++
+		}
+	}
+
+	if run.Total != len(succeeded) || run.Done != done || run.Failed != failed {
+		t.Errorf("run counters total %d done %d failed %d don't match the steps: total %d done %d failed %d", run.Total, run.Done, run.Failed, len(succeeded), done, failed)
+	}
+
+	for _, step := range status.FailedSteps {
+		if step.Success {
+			t.Error("successful step reported as failed", step)
+		}
+	}
+
+	if run.Status == database.RefreshCompleted && run.Failed > 0 {
+		t.Error("completed run has", run.Failed, "failed products")
+	}
+}
+
+func TestRefreshProduct(t *testing.T) {
+	if err := printful.RefreshProduct(679); err != nil {
+		t.Error(err)
+	}
+}
+
 func TestRefreshCountries(t *testing.T) {
 	printful.RefreshCountries()
 }
@@ -155,7 +309,7 @@ func TestRefreshCategories(t *testing.T) {
 }
 
 func TestTemplatesWithMultipleTechniques(t *testing.T) {
-	products, err := printful.GetProducts("en_US")
+	products, err := printful.GetProducts(printful.DefaultStoreID, "en_US")
 	if err != nil {
 		t.Error(err)
 		return
@@ -189,7 +343,7 @@ func TestTemplatesWithMultipleTechniques(t *testing.T) {
 }
 
 func TestProductPrices(t *testing.T) {
-	prices, err := printful.GetProductPrices(679, "USD")
+	prices, err := printful.GetProductPrices(printful.DefaultStoreID, 679, "USD")
 	if err != nil {
 		t.Error(err)
 		return
@@ -199,6 +353,101 @@ func TestProductPrices(t *testing.T) {
 
 }
 
+func TestPriceHistory(t *testing.T) {
+	useScratchSchema(t)
+
+	if err := printful.RefreshProduct(679); err != nil {
+		t.Error(err)
+		return
+	}
+
+	prices, _, err := database.FindProductPrices(679, "USD")
+	if err != nil {
+		t.Error(err)
+		return
+	}
+
+	if len(prices.Variants) == 0 || len(prices.Variants[0].Techniques) == 0 {
+		t.Error("product 679 should have prices")
+		return
+	}
+
+	// Change a stored price, the next refresh must record the actual price in the history
+	variantID := prices.Variants[0].ID
+	prices.Variants[0].Techniques[0].Price = "0.01"
+	if err = database.InsertProductPrices(prices); err != nil {
+		t.Error(err)
+		return
+	}
+
+	history, err := printful.GetPriceHistory(679, variantID, "USD")
+	if err != nil {
+		t.Error(err)
+		return
+	}
+
+	if err = printful.RefreshProduct(679); err != nil {
+		t.Error(err)
+		return
+	}
+
+	newHistory, err := printful.GetPriceHistory(679, variantID, "USD")
+	if err != nil {
+		t.Error(err)
+		return
+	}
+
+	if len(newHistory) <= len(history) {
+		t.Error("price change should write a history row for variant", variantID)
+	}
+
+	if _, err = printful.GetPriceChanges("USD", 30); err != nil {
+		t.Error(err)
+	}
+}
+
+func TestGetCatalogChanges(t *testing.T) {
+	useScratchSchema(t)
+
+	if err := printful.RefreshProduct(679); err != nil {
+		t.Error(err)
+		return
+	}
+
+	since := time.Now().Unix()
+
+	variantIDs, err := database.FindProductVariantIds(679)
+	if err != nil {
+		t.Error(err)
+		return
+	}
+
+	// Store a variant unknown to Printful, the next refresh must report it as removed
+	const removedVariantID = -1
+	if err = database.UpdateProductVariantIds(679, append(variantIDs, removedVariantID)); err != nil {
+		t.Error(err)
+		return
+	}
+
+	if err = printful.RefreshProduct(679); err != nil {
+		t.Error(err)
+		return
+	}
+
+	changes, err := printful.GetCatalogChanges(since)
+	if err != nil {
+		t.Error(err)
+		return
+	}
+
+	found := slices.ContainsFunc(changes, func(change database.CatalogChange) bool {
+		return change.ProductID == 679 && change.VariantID == removedVariantID && change.ChangeType == database.VariantRemoved
+	})
+	if !found {
+		t.Error("removed variant should be reported, got", changes)
+	}
+}
+
 func TestGetSimilarVariants(t *testing.T) {
 	testCases := make(map[int][]printful.GetSimilarVariantsPlacement)
 
@@ -228,3 +477,101 @@ func TestFindProducts(t *testing.T) {
 
 	log.Println("products: ", len(products))
 }
+
+// Fails when an action version is registered without documentation
+func TestOpenAPIDocument(t *testing.T) {
+	document, err := api.OpenAPIDocument()
+	if err != nil {
+		t.Error(err)
+		return
+	}
+
+	j, _ := json.MarshalIndent(&document, "", "\t")
+
+	err = os.WriteFile("./var/openapi.json", j, 0666)
+	if err != nil {
+		t.Error(err)
+		return
+	}
+}
+
+func TestApiKeys(t *testing.T) {
+	useScratchSchema(t)
+
+	key := database.ApiKey{
+		Name:           "test",
+		KeyHash:        strconv.FormatInt(time.Now().UnixNano(), 16),
+		Scopes:         []string{api.ScopeCatalogRead},
+		AllowedOrigins: []string{"https://example.com"},
+		DateCreated:    time.Now().Unix(),
+	}
+
+	id, err := database.InsertApiKey(&key)
+	if err != nil {
+		t.Error(err)
+		return
+	}
+
+	if err = database.RevokeApiKey(id); err != nil {
+		t.Error(err)
+		return
+	}
+
+	found, err := database.FindApiKeyByHash(key.KeyHash)
+	if err != nil {
+		t.Error(err)
+		return
+	}
+
+	if !found.Revoked {
+		t.Error("api key should be revoked")
+	}
+}
+
+func TestConsumeQuota(t *testing.T) {
+	useScratchSchema(t)
+
+	client := "test:" + strconv.FormatInt(time.Now().UnixNano(), 16)
+	day := time.Now().Unix() / 86400
+
+	if _, ok, err := database.ConsumeQuota(client, day, "orders", 2, 3); err != nil || !ok {
+		t.Error("quota should be consumed", err)
+		return
+	}
+
+	used, ok, err := database.ConsumeQuota(client, day, "orders", 2, 3)
+	if err != nil {
+		t.Error(err)
+		return
+	}
+
+	if ok || used != 2 {
+		t.Error("quota should be exceeded", used)
+		return
+	}
+
+	if err = database.RefundQuota(client, day, "orders", 2); err != nil {
+		t.Error(err)
+		return
+	}
+
+	if _, ok, err = database.ConsumeQuota(client, day, "orders", 3, 3); err != nil || !ok {
+		t.Error("refunded quota should be available", err)
+	}
+}
+
+func TestGetStoreConfig(t *testing.T) {
+	store, err := printful.GetStoreConfig("")
+	if err != nil {
+		t.Error(err)
+		return
+	}
+
+	if store.ID != printful.DefaultStoreID {
+		t.Error("empty store id should select the default store, got", store.ID)
+	}
+
+	if _, err = printful.GetStoreConfig("unknown-store"); err == nil {
+		t.Error("unknown store should return an error")
+	}
+}
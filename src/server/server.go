@@ -26,14 +26,25 @@ func initEngine() *gin.Engine {
 	r.SetTrustedProxies(nil)
 
 	r.Use(cors.New(cors.Config{
-		AllowMethods:    []string{"POST", "OPTIONS"},
-		AllowHeaders:    []string{"Origin", "Content-Length", "Content-Type", "Request-Id"},
-		AllowAllOrigins: true,
+		AllowMethods:    []string{"GET", "POST", "OPTIONS"},
+		AllowHeaders:    []string{"Origin", "Content-Length", "Content-Type", "Request-Id", "If-None-Match", "Authorization", "X-Api-Key", "X-Store-Id"},
+		ExposeHeaders:   []string{"ETag"},
+		AllowOriginFunc: api.AllowedOrigin,
 		MaxAge:          12 * time.Hour,
 	}))
 
 	r.POST("/api", api.ApiHandler)
 	r.GET("/image/:id", api.ImageHandler)
+	r.GET("/openapi.json", api.OpenAPIHandler)
+
+	r.GET("/products", api.RestHandler("get-products", ""))
+	r.GET("/products/:id", api.RestHandler("get-product", "product_id"))
+	r.GET("/products/:id/prices", api.RestHandler("get-product-prices", "product_id"))
+	r.GET("/products/:id/templates", api.RestHandler("get-mockup-templates", "product_id"))
+	r.GET("/products/:id/styles", api.RestHandler("get-mockup-styles", "product_id"))
+	r.GET("/variants/:id", api.RestHandler("get-variant", "variant_id"))
+	r.GET("/categories", api.RestHandler("get-categories", ""))
+	r.GET("/countries", api.RestHandler("get-countries", ""))
 
 	return r
 }
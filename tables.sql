@@ -151,18 +151,35 @@ CREATE TABLE products (
 	placements JSONB NOT NULL,
 	product_options JSONB NOT NULL,
 	date_created TIMESTAMP NOT NULL,
-	date_updated TIMESTAMP NOT NULL
+	date_updated TIMESTAMP NOT NULL,
+	search_document TSVECTOR GENERATED ALWAYS AS (to_tsvector('english', name || ' ' || description)) STORED
 );
 
+CREATE INDEX products_search_idx ON products USING GIN (search_document);
+
+-- The text search configuration of each language must match searchConfigs in src/database/search.go
 CREATE TABLE product_translations (
 	product_id INTEGER,
 	language TEXT,
 	name TEXT NOT NULL,
 	description TEXT NOT NULL,
 	last_updated BIGINT NOT NULL,
+	search_document TSVECTOR GENERATED ALWAYS AS (to_tsvector(
+		CASE language
+			WHEN 'en_US' THEN 'english'::REGCONFIG
+			WHEN 'en_GB' THEN 'english'::REGCONFIG
+			WHEN 'en_CA' THEN 'english'::REGCONFIG
+			WHEN 'es_ES' THEN 'spanish'::REGCONFIG
+			WHEN 'fr_FR' THEN 'french'::REGCONFIG
+			WHEN 'de_DE' THEN 'german'::REGCONFIG
+			WHEN 'it_IT' THEN 'italian'::REGCONFIG
+			ELSE 'simple'::REGCONFIG
+		END, name || ' ' || description)) STORED,
 	PRIMARY KEY (product_id, language)
 );
 
+CREATE INDEX product_translations_search_idx ON product_translations USING GIN (search_document);
+
 
 CREATE TYPE file_option_prices AS (
 	name TEXT,
@@ -239,3 +256,124 @@ CREATE TABLE variants (
 	availability JSONB NOT NULL,
 	last_updated BIGINT NOT NULL
 );
+
+CREATE TABLE variant_prices_history (
+	product_id INTEGER NOT NULL,
+	variant_id INTEGER NOT NULL,
+	currency TEXT NOT NULL,
+	technique_key TEXT NOT NULL,
+	price TEXT NOT NULL,
+	discounted_price TEXT NOT NULL,
+	date_recorded BIGINT NOT NULL
+);
+
+CREATE INDEX variant_prices_history_idx ON variant_prices_history (product_id, currency, date_recorded);
+
+CREATE TABLE catalog_changes (
+	id SERIAL PRIMARY KEY,
+	product_id INTEGER NOT NULL,
+	variant_id INTEGER NOT NULL,
+	change_type TEXT NOT NULL,
+	details JSONB NOT NULL,
+	date_created BIGINT NOT NULL
+);
+
+CREATE INDEX catalog_changes_date_idx ON catalog_changes (date_created);
+
+CREATE TABLE refresh_runs (
+	id SERIAL PRIMARY KEY,
+	currency TEXT NOT NULL,
+	use_cache BOOLEAN NOT NULL,
+	status TEXT NOT NULL,
+	total INTEGER NOT NULL,
+	done INTEGER NOT NULL,
+	failed INTEGER NOT NULL,
+	date_started BIGINT NOT NULL,
+	date_ended BIGINT NOT NULL
+);
+
+CREATE TABLE refresh_steps (
+	run_id INTEGER NOT NULL,
+	product_id INTEGER NOT NULL,
+	step TEXT NOT NULL,
+	success BOOLEAN NOT NULL,
+	pending BOOLEAN NOT NULL,
+	error TEXT NOT NULL,
+	date_updated BIGINT NOT NULL,
+	PRIMARY KEY (run_id, product_id, step)
+);
+
+CREATE TABLE variant_translations (
+	variant_id INTEGER,
+	language TEXT,
+	catalog_product_id INTEGER NOT NULL,
+	name TEXT NOT NULL,
+	color TEXT,
+	size TEXT NOT NULL,
+	last_updated BIGINT NOT NULL,
+	PRIMARY KEY (variant_id, language)
+);
+
+CREATE INDEX variant_translations_product_idx ON variant_translations (catalog_product_id, language);
+
+CREATE TABLE product_tagged_images (
+	product_id INTEGER NOT NULL,
+	tag TEXT NOT NULL,
+	image_url TEXT NOT NULL,
+	color TEXT NOT NULL,
+	mockup_style_id INTEGER NOT NULL,
+	last_updated BIGINT NOT NULL,
+	PRIMARY KEY (product_id, tag)
+);
+
+CREATE TABLE product_images (
+	product_id INTEGER PRIMARY KEY,
+	images JSONB NOT NULL,
+	last_updated BIGINT NOT NULL
+);
+
+CREATE TABLE mirrored_images (
+	source_url TEXT PRIMARY KEY,
+	image_id TEXT NOT NULL,
+	last_updated BIGINT NOT NULL
+);
+
+CREATE TABLE api_keys (
+	id SERIAL PRIMARY KEY,
+	name TEXT NOT NULL,
+	key_hash TEXT NOT NULL UNIQUE,
+	store_id TEXT NOT NULL,
+	scopes JSONB NOT NULL,
+	allowed_origins JSONB NOT NULL,
+	revoked BOOLEAN NOT NULL,
+	date_created BIGINT NOT NULL,
+	date_revoked BIGINT NOT NULL
+);
+
+CREATE TABLE api_usage (
+	client TEXT NOT NULL,
+	day INTEGER NOT NULL,
+	quota TEXT NOT NULL,
+	used BIGINT NOT NULL,
+	PRIMARY KEY (client, day, quota)
+);
+
+
+-- Upgrade of schemas created before the search documents. Safe to run on an up to date schema
+ALTER TABLE products ADD COLUMN IF NOT EXISTS search_document TSVECTOR GENERATED ALWAYS AS (to_tsvector('english', name || ' ' || description)) STORED;
+
+CREATE INDEX IF NOT EXISTS products_search_idx ON products USING GIN (search_document);
+
+ALTER TABLE product_translations ADD COLUMN IF NOT EXISTS search_document TSVECTOR GENERATED ALWAYS AS (to_tsvector(
+	CASE language
+		WHEN 'en_US' THEN 'english'::REGCONFIG
+		WHEN 'en_GB' THEN 'english'::REGCONFIG
+		WHEN 'en_CA' THEN 'english'::REGCONFIG
+		WHEN 'es_ES' THEN 'spanish'::REGCONFIG
+		WHEN 'fr_FR' THEN 'french'::REGCONFIG
+		WHEN 'de_DE' THEN 'german'::REGCONFIG
+		WHEN 'it_IT' THEN 'italian'::REGCONFIG
+		ELSE 'simple'::REGCONFIG
+	END, name || ' ' || description)) STORED;
+
+CREATE INDEX IF NOT EXISTS product_translations_search_idx ON product_translations USING GIN (search_document);
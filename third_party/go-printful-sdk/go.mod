@@ -0,0 +1,10 @@
+module github.com/baldurstod/go-printful-sdk
+
+go 1.22.4
+
+require (
+	github.com/icza/gox v0.2.0
+	golang.org/x/image v0.24.0
+	golang.org/x/sync v0.10.0
+	golang.org/x/time v0.9.0
+)
@@ -0,0 +1,75 @@
+package printfulsdk
+
+import (
+	"fmt"
+	"strconv"
+
+	"github.com/baldurstod/go-printful-sdk/model/responses"
+)
+
+type HTTPError struct {
+	err        error
+	statusCode int
+	context    any
+}
+
+func (e *HTTPError) Error() string {
+	return fmt.Sprintf("%s:%v", e.err.Error(), e.context)
+}
+
+func (e *HTTPError) Unwrap() error {
+	return e.err
+}
+
+// HTTP status code returned by Printful, 0 if unknown
+func (e *HTTPError) StatusCode() int {
+	return e.statusCode
+}
+
+// Error message returned by Printful, empty if the response had none
+func (e *HTTPError) Message() string {
+	switch response := e.context.(type) {
+	case *responses.Error4XXResponse:
+		if response.Error.Message != "" {
+			return response.Error.Message
+		}
+		return response.Result
+	case *responses.Error5XXResponse:
+		if response.Details != "" {
+			return response.Details
+		}
+		return response.Title
+	}
+	return ""
+}
+
+// Seconds to wait before retrying a request rejected with a 429 status, 0 if unknown
+func (e *HTTPError) RetryAfter() int {
+	response, ok := e.context.(map[string]interface{})
+	if !ok {
+		return 0
+	}
+
+	retryAfter, _ := response["retry-after"].(string)
+	seconds, err := strconv.Atoi(retryAfter)
+	if err != nil || seconds < 0 {
+		return 0
+	}
+	return seconds
+}
+
+func NewHTTPError(err error, context any) error {
+	return &HTTPError{
+		err:     err,
+		context: context,
+	}
+}
+
+// Error for a response with a status other than 200. context holds the decoded response, if any
+func NewHTTPStatusError(statusCode int, context any) error {
+	return &HTTPError{
+		err:        fmt.Errorf("printful returned HTTP status code: %d", statusCode),
+		statusCode: statusCode,
+		context:    context,
+	}
+}
@@ -0,0 +1,12 @@
+package printfulsdk
+
+var Languages = []string{
+	"en_US",
+	"en_GB",
+	"en_CA",
+	"es_ES",
+	"fr_FR",
+	"de_DE",
+	"it_IT",
+	"ja_JP",
+}
@@ -0,0 +1,6 @@
+package model
+
+type Color struct {
+	Name  string `json:"name" bson:"name" mapstructure:"name"`
+	Value string `json:"value" bson:"value" mapstructure:"value"`
+}
@@ -0,0 +1,8 @@
+package model
+
+type ProductOption interface {
+	IsProductOption()
+}
+
+// TODO: create unmarshaller
+type ProductOptions []ProductOption